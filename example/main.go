@@ -19,10 +19,7 @@ func main() {
 }
 
 func run(ctx context.Context) error {
-	clientId := os.Getenv("ARM_CLIENT_ID")
-	clientSecret := os.Getenv("ARM_CLIENT_SECRET")
 	subscriptionId := os.Getenv("ARM_SUBSCRIPTION_ID")
-	tenantId := os.Getenv("ARM_TENANT_ID")
 	rInt := time.Now().Unix()
 	name := fmt.Sprintf("tom-pandora-%d", rInt)
 	input := resourcegroups.CreateResourceGroupInput{
@@ -36,8 +33,11 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	auth := sdk.NewClientSecretAuthorizer(clientId, clientSecret, tenantId)
-	groupsClient := resourcegroups.NewClient(subscriptionId, auth)
+	auth, err := sdk.NewDefaultAuthorizer()
+	if err != nil {
+		return fmt.Errorf("building authorizer: %+v", err)
+	}
+	groupsClient := resourcegroups.NewResourceGroupsClient(subscriptionId, auth)
 	namespacesClient := eventhub.NewNamespacesClient(subscriptionId, auth)
 
 	id := resourcegroups.NewResourceGroupID(name)
@@ -91,27 +91,35 @@ func run(ctx context.Context) error {
 		Tags: map[string]string{},
 	}
 	log.Printf("Adding a EventHub Namespace %q", namespaceName)
-	poller, err := namespacesClient.Create(ctx, namespaceId, createNamespaceInput)
+	createPoller, err := namespacesClient.Create(ctx, namespaceId, createNamespaceInput, eventhub.CreateNamespaceOptions{})
 	if err != nil {
 		return fmt.Errorf("creating namespace: %+v", err)
 	}
 	log.Printf("Waiting for creation of %q", namespaceName)
-	if err := poller.PollUntilDone(ctx); err != nil {
+	if err := createPoller.PollUntilDone(ctx); err != nil {
 		return fmt.Errorf("waiting for creation: %+v", err)
 	}
 
-	log.Printf("Retrieving Namespace %q", namespaceName)
-	namespace, err := namespacesClient.Get(ctx, namespaceId)
+	// the Namespace is retrieved once it's been created, so there's no need to retrieve it separately
+	namespace := createPoller.Result()
+	log.Printf("ServiceBus Endpoint is at %q", namespace.Properties.ServiceBusEndpoint)
+	time.Sleep(10 * time.Second)
+
+	// only delete the Namespace if it hasn't been modified since it was retrieved
+	existing, err := namespacesClient.Get(ctx, namespaceId)
 	if err != nil {
 		return fmt.Errorf("retrieving namespace: %+v", err)
 	}
-
-	log.Printf("ServiceBus Endpoint is at %q", namespace.Namespace.Properties.ServiceBusEndpoint)
-	time.Sleep(10 * time.Second)
+	deleteOptions := eventhub.DeleteNamespaceOptions{
+		IfMatch: existing.ETag,
+	}
 
 	log.Printf("Deleting EH namespace %q", namespaceName)
-	poller, err = namespacesClient.Delete(ctx, namespaceId)
+	poller, err := namespacesClient.Delete(ctx, namespaceId, deleteOptions)
 	if err != nil {
+		if sdk.IsPreconditionFailed(err) {
+			return fmt.Errorf("deleting namespace: %q was modified since it was retrieved: %+v", namespaceName, err)
+		}
 		return fmt.Errorf("deleting namespace: %+v", err)
 	}
 	log.Printf("Waiting for deletion of %q", namespaceName)
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/tombuildsstuff/pandora/generator/models"
+	"github.com/tombuildsstuff/pandora/generator/utils"
+	"github.com/tombuildsstuff/pandora/sdk"
+)
+
+// packageDefinition describes a Resource Manager package to generate, which contains a single resource
+type packageDefinition struct {
+	// directory is the directory the package is generated into, within the outputDirectory
+	directory string
+
+	// filePrefix is the prefix for the generated files, e.g. `namespaces` outputs `namespaces_client.go`
+	filePrefix string
+
+	packageName      string
+	typeName         string
+	apiVersion       string
+	resourceProvider *string
+	operations       []models.OperationMetaData
+	segments         []sdk.Segment
+}
+
+func resourceManagerPackages() []packageDefinition {
+	return []packageDefinition{
+		eventHubNamespaces(),
+		resourceGroups(),
+	}
+}
+
+func eventHubNamespaces() packageDefinition {
+	return packageDefinition{
+		directory:        "eventhubs/2018-01-01-preview/eventhub",
+		filePrefix:       "namespaces",
+		packageName:      "eventhub",
+		typeName:         "Namespace",
+		apiVersion:       "2018-01-01-preview",
+		resourceProvider: utils.String("Microsoft.EventHub"),
+		operations: []models.OperationMetaData{
+			{
+				Name:                 "Create",
+				Method:               http.MethodPut,
+				LongRunningOperation: true,
+				ConditionalRequests:  true,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+				HasResponseBody: true,
+			},
+			{
+				Name:                 "Delete",
+				Method:               http.MethodDelete,
+				LongRunningOperation: true,
+				ConditionalRequests:  true,
+				ExpectedStatusCodes: []int{
+					202,
+				},
+			},
+			{
+				Name:   "Get",
+				Method: http.MethodGet,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+			},
+			{
+				Name:     "List",
+				Method:   http.MethodGet,
+				Pageable: true,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+				ResourceIdName: utils.String("sdk.SubscriptionID"),
+				UriSuffix:      utils.String("/providers/Microsoft.EventHub/namespaces"),
+			},
+			{
+				Name:     "ListByResourceGroup",
+				Method:   http.MethodGet,
+				Pageable: true,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+				ResourceIdName: utils.String("sdk.ResourceGroupID"),
+				UriSuffix:      utils.String("/providers/Microsoft.EventHub/namespaces"),
+			},
+		},
+		segments: []sdk.Segment{
+			sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+			sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+			sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+			sdk.ResourceGroupSegment("resourceGroup", "example-resources"),
+			sdk.StaticSegment("staticProviders", "providers"),
+			sdk.ResourceProviderSegment("staticMicrosoftEventHub", "Microsoft.EventHub"),
+			sdk.StaticSegment("staticNamespaces", "namespaces"),
+			sdk.UserSpecifiedSegment("name", "example-namespace"),
+		},
+	}
+}
+
+// resourceGroups doesn't support conditional requests, since Resource Groups don't return an ETag
+func resourceGroups() packageDefinition {
+	return packageDefinition{
+		directory:        "resources/2018-05-01/resourcegroups",
+		filePrefix:       "groups",
+		packageName:      "resourcegroups",
+		typeName:         "ResourceGroup",
+		apiVersion:       "2018-05-01",
+		resourceProvider: utils.String("Microsoft.Resources"),
+		operations: []models.OperationMetaData{
+			{
+				Name:   "Create",
+				Method: http.MethodPut,
+				ExpectedStatusCodes: []int{
+					200,
+					201,
+				},
+			},
+			{
+				Name:                 "Delete",
+				Method:               http.MethodDelete,
+				LongRunningOperation: true,
+				ExpectedStatusCodes: []int{
+					202,
+				},
+			},
+			{
+				Name:   "ExportTemplate",
+				Method: http.MethodPost,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+				HasRequestBody:  true,
+				HasResponseBody: true,
+				UriSuffix:       utils.String("/exportTemplate"),
+			},
+			{
+				Name:   "Get",
+				Method: http.MethodGet,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+			},
+			{
+				Name:     "List",
+				Method:   http.MethodGet,
+				Pageable: true,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+				ResourceIdName: utils.String("sdk.SubscriptionID"),
+				UriSuffix:      utils.String("/resourcegroups"),
+			},
+			{
+				Name:   "Update",
+				Method: http.MethodPatch,
+				ExpectedStatusCodes: []int{
+					200,
+				},
+			},
+		},
+		segments: []sdk.Segment{
+			sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+			sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+			sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+			sdk.UserSpecifiedSegment("name", "example-resources"),
+		},
+	}
+}
@@ -1,92 +1,51 @@
 package main
 
 import (
+	"fmt"
+	"io/ioutil"
 	"log"
-	"net/http"
+	"path/filepath"
 
-	"github.com/tombuildsstuff/pandora/generator/models"
 	"github.com/tombuildsstuff/pandora/generator/templates"
 	"github.com/tombuildsstuff/pandora/generator/utils"
 )
 
-func main() {
-	if err := clientMain(); err != nil {
-		panic(err)
-	}
-}
-
-func clientMain() error {
-	methods := []models.OperationMetaData{
-		{
-			Name:                 "Delete",
-			Method:               http.MethodDelete,
-			LongRunningOperation: true,
-			ExpectedStatusCodes: []int{
-				200,
-			},
-		},
-		{
-			Name:                 "Get",
-			Method:               http.MethodGet,
-			LongRunningOperation: false,
-			ExpectedStatusCodes: []int{
-				200,
-			},
-		},
-		{
-			Name:                 "Create",
-			Method:               http.MethodPut,
-			LongRunningOperation: true,
-			ExpectedStatusCodes: []int{
-				200,
-				201,
-			},
-		},
-		{
-			Name:                 "Update",
-			Method:               http.MethodPatch,
-			LongRunningOperation: true,
-			ExpectedStatusCodes: []int{
-				200,
-				201,
-			},
-		},
-	}
-	//templater := templates.NewClientTemplater("example", "EventHubNamespace", "2018-01-01", nil, methods)
-	templater := templates.NewModelsTemplater("example", "EventHubNamespace", methods)
-	out, err := templater.Build()
-	if err != nil {
-		return err
-	}
-	//log.Printf("---\n%s\n---", *out)
+// outputDirectory is the directory the Resource Manager packages are generated into, relative to the repository root
+const outputDirectory = "resource-manager"
 
-	fmt, err := utils.GolangCodeFormatter{}.Format(*out)
-	if err != nil {
-		return err
+func main() {
+	for _, definition := range resourceManagerPackages() {
+		if err := generatePackage(definition); err != nil {
+			panic(fmt.Errorf("generating %q: %+v", definition.directory, err))
+		}
 	}
-
-	log.Printf("---\n%s\n---", *fmt)
-	return nil
 }
 
-func resourceIdMain() error {
-	formatString := "/subscriptions/{subscriptionId}/resourceGroups/{name}/providers/Microsoft.EventHub/namespaces/{namespace}"
-	segments := []string{
-		"subscriptionId",
-		"resourceGroup",
-		"namespace",
-	}
-	template := templates.NewResourceIDTemplate("example", "EventHubNamespace", formatString, segments)
-	output, err := template.Build()
-	if err != nil {
-		return err
+// generatePackage generates the Client and Resource ID for the package - the models are written by hand
+// since the API Definitions aren't parsed yet, as such the models template only outputs placeholders
+func generatePackage(definition packageDefinition) error {
+	files := map[string]templates.TemplateBuilder{
+		fmt.Sprintf("%s_client.go", definition.filePrefix): templates.NewClientTemplater(definition.packageName, definition.typeName, definition.apiVersion, definition.resourceProvider, definition.operations),
+		fmt.Sprintf("%s_id.go", definition.filePrefix):     templates.NewResourceIDTemplate(definition.packageName, definition.typeName, definition.segments),
 	}
 
-	fmt, err := utils.GolangCodeFormatter{}.Format(*output)
-	if err != nil {
-		return err
+	for fileName, templater := range files {
+		out, err := templater.Build()
+		if err != nil {
+			return fmt.Errorf("building %q: %+v", fileName, err)
+		}
+
+		formatted, err := utils.GolangCodeFormatter{}.Format(*out)
+		if err != nil {
+			return fmt.Errorf("formatting %q: %+v", fileName, err)
+		}
+
+		filePath := filepath.Join(outputDirectory, definition.directory, fileName)
+		if err := ioutil.WriteFile(filePath, []byte(*formatted), 0644); err != nil {
+			return fmt.Errorf("writing %q: %+v", filePath, err)
+		}
+		log.Printf("Generated %q", filePath)
 	}
 
-	log.Printf("---\n%s\n---", *fmt)
 	return nil
 }
@@ -6,4 +6,55 @@ type OperationMetaData struct {
 	Method               string
 	LongRunningOperation bool
 	ExpectedStatusCodes  []int
+
+	// ConditionalRequests specifies that this operation supports the `If-Match` and `If-None-Match` headers,
+	// which are exposed as options on write (`DELETE`, `PATCH` and `PUT`) operations
+	ConditionalRequests bool
+
+	// HasRequestBody specifies that this operation accepts a request body, which is only used for `POST` operations
+	// since `PATCH` and `PUT` operations always have one
+	HasRequestBody bool
+
+	// FinalStateVia specifies where the final result of a long-running operation is retrieved from, matching the
+	// `final-state-via` option in the API Definition (`azure-async-operation`, `location` or `original-uri`)
+	FinalStateVia *string
+
+	// HasResponseBody specifies that this operation returns a response body, which is only used for `POST` operations
+	// and long-running operations - where this is the final result of the operation
+	HasResponseBody bool
+
+	// Options are the optional query string and header parameters for this operation
+	Options []OperationOption
+
+	// Pageable specifies that this is a list operation, whose results are split across pages
+	Pageable bool
+
+	// ResourceIdName is the name of the Resource ID type this operation is performed against,
+	// defaulting to the Resource ID for the type when unspecified
+	ResourceIdName *string
+
+	// ScopedResourceId specifies that the Resource ID this operation is performed against is a Scoped Resource ID
+	// (implementing `sdk.ScopedResourceId`), such as a Management Group or an extension resource - and as such
+	// the URI for this operation doesn't include the Subscription the client is configured for
+	ScopedResourceId bool
+
+	// UriSuffix is appended to the Resource ID to build the URI for this operation,
+	// for example `/providers/Microsoft.EventHub/namespaces` when listing Namespaces
+	// or `/listKeys` for an action
+	UriSuffix *string
+}
+
+// OperationOption is an optional query string or header parameter for an operation
+type OperationOption struct {
+	// Name is the name of the field within the Options struct for this operation, e.g. `Expand`
+	Name string
+
+	// Type is the Go type of this option, e.g. `string` or `bool`
+	Type string
+
+	// HeaderName is the name of the header this option is sent as, e.g. `If-Match`
+	HeaderName *string
+
+	// QueryStringName is the name of the query string parameter this option is sent as, e.g. `$expand`
+	QueryStringName *string
 }
@@ -42,7 +42,7 @@ import (
 	"net/http"
 
 	"github.com/tombuildsstuff/pandora/sdk"
-	"github.com/tombuildsstuff/pandora/sdk/endpoints"
+	"github.com/tombuildsstuff/pandora/sdk/environments"
 )
 
 type %[2]ssClient struct {
@@ -62,13 +62,17 @@ type %[2]ssClient struct {
 func (t ClientTemplater) constructors() string {
 	template := fmt.Sprintf(`
 func New%[1]ssClient(subscriptionId string, authorizer sdk.Authorizer) %[1]ssClient {
-	return New%[1]ssClientWithBaseURI(endpoints.DefaultManagementEndpoint, subscriptionId, authorizer)
+	return New%[1]ssClientWithEnvironment(environments.Default, subscriptionId, authorizer)
 }
 
-func New%[1]ssClientWithBaseURI(endpoint string, subscriptionId string, authorizer sdk.Authorizer) %[1]ssClient {
+func New%[1]ssClientWithEnvironment(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer) %[1]ssClient {
+	return New%[1]ssClientWithOptions(environment, subscriptionId, authorizer, sdk.ClientOptions{})
+}
+
+func New%[1]ssClientWithOptions(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer, options sdk.ClientOptions) %[1]ssClient {
 	return %[1]ssClient{
 		apiVersion:     "%s",
-		baseClient:     sdk.DefaultBaseClient(endpoint, authorizer),
+		baseClient:     sdk.NewBaseClient(environment, authorizer, options),
 		subscriptionId: subscriptionId,
 	}
 }
@@ -110,6 +114,14 @@ func (t ClientTemplater) methods() (*string, error) {
 			name:                 method.Name,
 			longRunningOperation: method.LongRunningOperation,
 			expectedStatusCodes:  method.ExpectedStatusCodes,
+			finalStateVia:        method.FinalStateVia,
+			hasRequestBody:       method.HasRequestBody,
+			hasResponseBody:      method.HasResponseBody,
+			options:              optionsForOperation(method),
+			pageable:             method.Pageable,
+			resourceIdName:       method.ResourceIdName,
+			scopedResourceId:     method.ScopedResourceId,
+			uriSuffix:            method.UriSuffix,
 		}
 		formatted, err := templater.Build()
 		if err != nil {
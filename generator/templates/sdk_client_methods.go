@@ -3,6 +3,8 @@ package templates
 import (
 	"fmt"
 	"strings"
+
+	"github.com/tombuildsstuff/pandora/generator/models"
 )
 
 type methodTemplater struct {
@@ -11,9 +13,23 @@ type methodTemplater struct {
 	method               string
 	longRunningOperation bool
 	expectedStatusCodes  []int
+	finalStateVia        *string
+	hasRequestBody       bool
+	hasResponseBody      bool
+	options              []models.OperationOption
+	pageable             bool
+	resourceIdName       *string
+	scopedResourceId     bool
+	uriSuffix            *string
 }
 
 func (t methodTemplater) Build() (*string, error) {
+	if t.longRunningOperation {
+		if _, err := t.finalStateViaValue(); err != nil {
+			return nil, err
+		}
+	}
+
 	var result string
 	switch strings.ToUpper(t.method) {
 	case "DELETE":
@@ -33,6 +49,11 @@ func (t methodTemplater) Build() (*string, error) {
 				return nil, fmt.Errorf("`GET` operations cannot be long-running")
 			}
 
+			if t.pageable {
+				result = t.list()
+				break
+			}
+
 			result = t.get()
 			break
 		}
@@ -48,6 +69,17 @@ func (t methodTemplater) Build() (*string, error) {
 			break
 		}
 
+	case "POST":
+		{
+			if t.longRunningOperation {
+				result = t.postLongRunningOperation()
+				break
+			}
+
+			result = t.post()
+			break
+		}
+
 	case "PUT":
 		{
 			if t.longRunningOperation {
@@ -68,135 +100,349 @@ func (t methodTemplater) Build() (*string, error) {
 }
 
 func (t methodTemplater) delete() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID) (*http.Response, error) {
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[7]s) (*http.Response, error) {
 	req := sdk.DeleteHttpRequestInput{
 		ExpectedStatusCodes: []int{
 %[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 	
 	return client.baseClient.Delete(ctx, req);
 }
-`, t.typeName, t.name, statusCodes)
+`, t.templateArguments()...)
 }
 
 func (t methodTemplater) deleteLongRunningOperation() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID) (sdk.Poller, error) {
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[7]s) (sdk.Poller, error) {
 	req := sdk.DeleteHttpRequestInput{
 		ExpectedStatusCodes: []int{
-%[3]s,
+%[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 
 	return client.baseClient.DeleteThenPoll(ctx, req)
 }
-`, t.typeName, t.name, statusCodes)
+`, t.templateArguments()...)
 }
 
 func (t methodTemplater) get() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID) (*%[2]s%[1]sResponse, error) {
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[7]s) (*%[2]s%[1]sResponse, error) {
 	req := sdk.GetHttpRequestInput{
 		ExpectedStatusCodes: []int{
-%[3]s,
+%[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 
 	var out %[2]s%[1]s
 	resp, err := client.baseClient.GetJson(ctx, req, &out)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %%+v", err)
+		return nil, fmt.Errorf("sending Request: %%w", err)
 	}
 
 	result := %[2]s%[1]sResponse{
+		ETag:         sdk.ETagFromResponse(resp),
 		HttpResponse: resp,
 		%[1]s:    &out,
 	}
 	return &result, nil
 }
-`, t.typeName, t.name, statusCodes)
+`, t.templateArguments()...)
+}
+
+// list returns a method returning a Pager for the resource model (see resourceModelName), which is the
+// same model as is returned from the Get operation
+func (t methodTemplater) list() string {
+	// lists within the Subscription the client is configured for don't need a Resource ID
+	idArgument := fmt.Sprintf("id %s, ", t.resourceIdType())
+	if t.subscriptionScoped() {
+		idArgument = ""
+	}
+
+	return fmt.Sprintf(`
+func (client %[1]ssClient) %[2]s(%[10]soptions %[2]s%[1]sOptions) *sdk.Pager[%[11]s] {
+	req := sdk.ListHttpRequestInput{
+		ExpectedStatusCodes: []int{
+%[3]s
+		},
+		Filter: options.Filter,
+		Top:    options.Top,
+%[8]s		Uri:    %[9]s,
+	}
+
+	return sdk.NewPager[%[11]s](client.baseClient, req)
+}
+`, append(t.templateArguments(), idArgument, resourceModelName(t.typeName))...)
 }
 
 func (t methodTemplater) patch() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID, input %[2]s%[1]sInput) error {
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s, input %[2]s%[1]sInput%[7]s) error {
 	req := sdk.PatchHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
 %[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 	
 	if _, err := client.baseClient.PatchJson(ctx, req); err != nil {
-		return fmt.Errorf("sending Request: %%+v", err)
+		return fmt.Errorf("sending Request: %%w", err)
 	}
 	return nil
 }
-`, t.typeName, t.name, statusCodes)
+`, t.templateArguments()...)
 }
 
 func (t methodTemplater) patchLongRunningOperation() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID, input %[2]s%[1]sInput) (sdk.Poller, error) {
-	req := sdk.Patch%[1]sInput{
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s, input %[2]s%[1]sInput%[7]s) (%[10]s, error) {
+	req := sdk.PatchHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
-%[3]s,
+%[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 
-	return client.baseClient.PatchJsonThenPoll(ctx, req)
+%[11]s
 }
-`, t.typeName, t.name, statusCodes)
+`, t.longRunningOperationArguments("PatchJsonThenPoll")...)
+}
+
+func (t methodTemplater) post() string {
+	if !t.hasResponseBody {
+		return fmt.Sprintf(`
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[5]s%[7]s) error {
+	req := sdk.PostHttpRequestInput{%[6]s
+		ExpectedStatusCodes: []int{
+%[3]s
+		},
+%[8]s		Uri: %[9]s,
+	}
+
+	if _, err := client.baseClient.PostJson(ctx, req, nil); err != nil {
+		return fmt.Errorf("sending Request: %%w", err)
+	}
+	return nil
+}
+`, t.templateArguments()...)
+	}
+
+	return fmt.Sprintf(`
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[5]s%[7]s) (*%[2]s%[1]sResponse, error) {
+	req := sdk.PostHttpRequestInput{%[6]s
+		ExpectedStatusCodes: []int{
+%[3]s
+		},
+%[8]s		Uri: %[9]s,
+	}
+
+	var out %[2]s%[1]s
+	resp, err := client.baseClient.PostJson(ctx, req, &out)
+	if err != nil {
+		return nil, fmt.Errorf("sending Request: %%w", err)
+	}
+
+	result := %[2]s%[1]sResponse{
+		HttpResponse: resp,
+		Model:        &out,
+	}
+	return &result, nil
+}
+`, t.templateArguments()...)
+}
+
+func (t methodTemplater) postLongRunningOperation() string {
+	return fmt.Sprintf(`
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s%[5]s%[7]s) (%[10]s, error) {
+	req := sdk.PostHttpRequestInput{%[6]s
+		ExpectedStatusCodes: []int{
+%[3]s
+		},
+%[8]s		Uri: %[9]s,
+	}
+
+%[11]s
+}
+`, t.longRunningOperationArguments("PostJsonThenPoll")...)
+}
+
+// postRequestBody returns the additional method argument and request field used to send the
+// request body for a `POST` operation, which are empty when the operation has no request body
+func (t methodTemplater) postRequestBody() (string, string) {
+	if !t.hasRequestBody {
+		return "", ""
+	}
+
+	arguments := fmt.Sprintf(", input %s%sInput", t.name, t.typeName)
+	body := "\n\t\tBody: input,"
+	return arguments, body
 }
 
 func (t methodTemplater) put() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID, input %[2]s%[1]sInput) error {
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s, input %[2]s%[1]sInput%[7]s) error {
 	req := sdk.PutHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
 %[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
 	}
 	
 	if _, err := client.baseClient.PutJson(ctx, req); err != nil {
-		return fmt.Errorf("sending Request: %%+v", err)
+		return fmt.Errorf("sending Request: %%w", err)
 	}
 	return nil
 }
-`, t.typeName, t.name, statusCodes)
+`, t.templateArguments()...)
 }
 
 func (t methodTemplater) putLongRunningOperation() string {
-	statusCodes := t.statusCodes("\t\t\t")
 	return fmt.Sprintf(`
-func (client %[1]ssClient) %[2]s(ctx context.Context, id %[1]sID, input %[2]s%[1]sInput) (sdk.Poller, error) {
-	req := sdk.Put%[1]sInput{
+func (client %[1]ssClient) %[2]s(ctx context.Context, id %[4]s, input %[2]s%[1]sInput%[7]s) (%[10]s, error) {
+	req := sdk.PutHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
-%[3]s,
+%[3]s
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+%[8]s		Uri: %[9]s,
+	}
+
+%[11]s
+}
+`, t.longRunningOperationArguments("PutJsonThenPoll")...)
+}
+
+// templateArguments returns the arguments available to each method template, which are (in order):
+// the type name, the method name, the expected status codes, the Resource ID type, the request body argument
+// and field (for POST operations), the options argument and fields and the URI
+func (t methodTemplater) templateArguments() []interface{} {
+	requestBodyArgument, requestBodyField := t.postRequestBody()
+	optionsArgument, optionsFields := t.optionsArgumentAndFields()
+	return []interface{}{
+		t.typeName,
+		t.name,
+		t.statusCodes("\t\t\t"),
+		t.resourceIdType(),
+		requestBodyArgument,
+		requestBodyField,
+		optionsArgument,
+		optionsFields,
+		t.uri(),
+	}
+}
+
+// longRunningOperationArguments returns the arguments available to the long-running method templates, which are
+// those from templateArguments followed by the return type and the statements used to start polling using the
+// specified method on the BaseClient
+func (t methodTemplater) longRunningOperationArguments(thenPollMethod string) []interface{} {
+	returnType := "sdk.Poller"
+	statements := fmt.Sprintf("\treturn client.baseClient.%s(ctx, req)", thenPollMethod)
+
+	// when the operation has a response body, the final result is retrieved once the operation has completed
+	if t.hasResponseBody {
+		finalStateVia, _ := t.finalStateViaValue()
+		modelName := fmt.Sprintf("%s%s", t.name, t.typeName)
+		if returnsResourceModelWhenCompleted(t.method, t.longRunningOperation, t.hasResponseBody) {
+			modelName = resourceModelName(t.typeName)
+		}
+		returnType = fmt.Sprintf("*sdk.ResultPoller[%s]", modelName)
+		statements = fmt.Sprintf(`	poller, err := client.baseClient.%[1]s(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdk.NewResultPoller[%[2]s](poller, &client.baseClient, %[3]s), nil`, thenPollMethod, modelName, finalStateVia)
+	}
+
+	return append(t.templateArguments(), returnType, statements)
+}
+
+// finalStateViaValue returns the `sdk.FinalStateVia` constant used to retrieve the final result of a long-running operation
+func (t methodTemplater) finalStateViaValue() (string, error) {
+	if t.finalStateVia == nil {
+		return "sdk.FinalStateViaDefault", nil
+	}
+
+	values := map[string]string{
+		"azure-async-operation": "sdk.FinalStateViaAzureAsyncOperation",
+		"location":              "sdk.FinalStateViaLocation",
+		"original-uri":          "sdk.FinalStateViaOriginalUri",
+	}
+	if v, ok := values[strings.ToLower(*t.finalStateVia)]; ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("unsupported final-state-via %q for %q", *t.finalStateVia, t.name)
+}
+
+// uri returns the expression used to build the URI for this operation, which for Scoped Resource IDs
+// doesn't include the Subscription the client is configured for
+func (t methodTemplater) uri() string {
+	if t.scopedResourceId {
+		if t.uriSuffix != nil {
+			return fmt.Sprintf("sdk.BuildScopedResourceManagerURIWithSuffix(id, %q, client.apiVersion)", *t.uriSuffix)
+		}
+
+		return "sdk.BuildScopedResourceManagerURI(id, client.apiVersion)"
 	}
 
-	return client.baseClient.PutJsonThenPoll(ctx, req)
+	id := "id"
+	if t.subscriptionScoped() {
+		id = "sdk.NewSubscriptionID()"
+	}
+
+	if t.uriSuffix != nil {
+		return fmt.Sprintf("sdk.BuildResourceManagerURIWithSuffix(%s, %q, client.subscriptionId, client.apiVersion)", id, *t.uriSuffix)
+	}
+
+	return fmt.Sprintf("sdk.BuildResourceManagerURI(%s, client.subscriptionId, client.apiVersion)", id)
+}
+
+// subscriptionScoped returns whether this is a list operation within the Subscription the client is configured for,
+// in which case the URI is built from the client's Subscription rather than a Resource ID passed to the method
+func (t methodTemplater) subscriptionScoped() bool {
+	return t.pageable && t.resourceIdName != nil && *t.resourceIdName == "sdk.SubscriptionID"
+}
+
+// optionsArgumentAndFields returns the additional method argument and request fields used to send the
+// optional query string and header parameters for this operation, which are empty when there are none
+func (t methodTemplater) optionsArgumentAndFields() (string, string) {
+	fields := make([]string, 0)
+	if hasOptionsOfKind(t.options, true) {
+		fields = append(fields, "\t\tHeaders: options.toHeaders(),\n")
+	}
+	if hasOptionsOfKind(t.options, false) {
+		fields = append(fields, "\t\tQueryParameters: options.toQueryParameters(),\n")
+	}
+
+	// list operations always accept an Options struct, since these support `$filter` and `$top`
+	if t.pageable {
+		return "", strings.Join(fields, "")
+	}
+
+	if len(t.options) == 0 {
+		return "", ""
+	}
+
+	argument := fmt.Sprintf(", options %s%sOptions", t.name, t.typeName)
+	return argument, strings.Join(fields, "")
 }
-`, t.typeName, t.name, statusCodes)
+
+// resourceIdType returns the name of the Resource ID type this method is performed against
+func (t methodTemplater) resourceIdType() string {
+	if t.resourceIdName != nil {
+		return *t.resourceIdName
+	}
+
+	return fmt.Sprintf("%sID", t.typeName)
 }
 
 func (t methodTemplater) statusCodes(indentation string) string {
@@ -235,6 +481,28 @@ func descriptionForStatusCodeForMethod(code int, method string, longRunningOpera
 		}
 	}
 
+	if strings.EqualFold(method, "patch") || strings.EqualFold(method, "put") {
+		knownStatusCodes = map[int]string{
+			200: "ok",
+			201: "created",
+			202: "accepted",
+		}
+	}
+
+	if strings.EqualFold(method, "post") {
+		if longRunningOperation {
+			knownStatusCodes = map[int]string{
+				200: "completed",
+				202: "accepted",
+			}
+		} else {
+			knownStatusCodes = map[int]string{
+				200: "ok",
+				204: "no content",
+			}
+		}
+	}
+
 	// TODO: others
 
 	v, ok := knownStatusCodes[code]
@@ -56,6 +56,24 @@ func (t ModelsTemplater) models() (*string, error) {
 
 			types[k] = v
 		}
+
+		if len(optionsForOperation(operation)) > 0 || operation.Pageable {
+			structName := fmt.Sprintf("%s%sOptions", operation.Name, t.typeName)
+			if _, existing := types[structName]; existing {
+				return nil, fmt.Errorf("invalid duplicate type for %q", structName)
+			}
+
+			types[structName] = t.optionsTypes(operation, structName)
+		}
+	}
+
+	// list operations and long-running `PATCH`/`PUT` operations return the resource model, which is otherwise
+	// output by the Get operation
+	if _, existing := types[resourceModelName(t.typeName)]; !existing && t.requiresResourceModel() {
+		structName := resourceModelName(t.typeName)
+		types[structName] = fmt.Sprintf(`type %s struct {
+	// TODO: implementation
+}`, structName)
 	}
 
 	sortedKeys := make([]string, 0)
@@ -74,6 +92,17 @@ func (t ModelsTemplater) models() (*string, error) {
 	return &result, nil
 }
 
+// requiresResourceModel returns whether any of the operations return the resource model
+func (t ModelsTemplater) requiresResourceModel() bool {
+	for _, operation := range t.operations {
+		if operation.Pageable || returnsResourceModelWhenCompleted(operation.Method, operation.LongRunningOperation, operation.HasResponseBody) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t ModelsTemplater) typesForOperation(input models.OperationMetaData, typeName string) (*map[string]string, error) {
 	method := strings.ToUpper(input.Method)
 	if method == "DELETE" {
@@ -83,6 +112,11 @@ func (t ModelsTemplater) typesForOperation(input models.OperationMetaData, typeN
 
 	// TODO: validation methods
 
+	if method == "GET" && input.Pageable {
+		// list operations return the same model as the Get operation, see resourceModelName
+		return &map[string]string{}, nil
+	}
+
 	if method == "GET" {
 		result := t.getOperationTypes(input, typeName)
 		return &result, nil
@@ -93,6 +127,11 @@ func (t ModelsTemplater) typesForOperation(input models.OperationMetaData, typeN
 		return &result, nil
 	}
 
+	if method == "POST" {
+		result := t.postOperationTypes(input, typeName)
+		return &result, nil
+	}
+
 	if method == "PUT" {
 		result := t.putOperationTypes(input, typeName)
 		return &result, nil
@@ -110,27 +149,185 @@ func (t ModelsTemplater) getOperationTypes(input models.OperationMetaData, typeN
 	// TODO: implementation
 }`, structName),
 		wrapperStructName: fmt.Sprintf(`type %[1]s struct {
-	HttpResponse  *http.Response
-	ResourceGroup *%[2]s
-}`, wrapperStructName, structName),
+	// ETag is the current version of the resource, which can be used for conditional requests
+	ETag         *string
+	HttpResponse *http.Response
+	%[3]s    *%[2]s
+}`, wrapperStructName, structName, typeName),
 	}
 }
 
+// resourceModelName returns the name of the model for the resource, which is returned by both the Get operation
+// and list operations (since these return the same model for each item)
+func resourceModelName(typeName string) string {
+	return fmt.Sprintf("Get%s", typeName)
+}
+
+// returnsResourceModelWhenCompleted returns whether the final result of a long-running operation is the resource
+// model, which is the case for `PATCH` and `PUT` operations since the result is retrieved from the original URI
+func returnsResourceModelWhenCompleted(method string, longRunningOperation, hasResponseBody bool) bool {
+	method = strings.ToUpper(method)
+	return longRunningOperation && hasResponseBody && (method == "PATCH" || method == "PUT")
+}
+
+// optionsTypes returns the Options struct for the optional query string and header parameters of an operation,
+// along with the methods used to convert these into the Headers and QueryParameters for the request
+func (t ModelsTemplater) optionsTypes(input models.OperationMetaData, structName string) string {
+	fields := make([]string, 0)
+	if input.Pageable {
+		fields = append(fields, "\tFilter *string", "\tTop    *int")
+	}
+	options := optionsForOperation(input)
+	for _, option := range options {
+		fields = append(fields, fmt.Sprintf("\t%s *%s", option.Name, option.Type))
+	}
+
+	output := fmt.Sprintf(`type %s struct {
+%s
+}`, structName, strings.Join(fields, "\n"))
+
+	if hasOptionsOfKind(options, true) {
+		output += t.optionsConversionMethod(options, structName, "toHeaders", true)
+	}
+	if hasOptionsOfKind(options, false) {
+		output += t.optionsConversionMethod(options, structName, "toQueryParameters", false)
+	}
+
+	return output
+}
+
+func (t ModelsTemplater) optionsConversionMethod(options []models.OperationOption, structName, methodName string, headers bool) string {
+	lines := make([]string, 0)
+	for _, option := range options {
+		key := option.QueryStringName
+		if headers {
+			key = option.HeaderName
+		}
+		if key == nil {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf(`	if o.%[1]s != nil {
+		output[%[2]q] = fmt.Sprintf("%%v", *o.%[1]s)
+	}`, option.Name, *key))
+	}
+
+	return fmt.Sprintf(`
+
+func (o %[1]s) %[2]s() map[string]string {
+	output := make(map[string]string)
+%[3]s
+	return output
+}`, structName, methodName, strings.Join(lines, "\n"))
+}
+
+// optionsForOperation returns the optional query string and header parameters for the operation, including the
+// `If-Match` and `If-None-Match` headers for write operations which support conditional requests
+func optionsForOperation(input models.OperationMetaData) []models.OperationOption {
+	output := make([]models.OperationOption, 0)
+	output = append(output, input.Options...)
+
+	method := strings.ToUpper(input.Method)
+	isWriteOperation := method == "DELETE" || method == "PATCH" || method == "PUT"
+	if input.ConditionalRequests && isWriteOperation {
+		ifMatch := "If-Match"
+		ifNoneMatch := "If-None-Match"
+		output = append(output, models.OperationOption{
+			Name:       "IfMatch",
+			Type:       "string",
+			HeaderName: &ifMatch,
+		}, models.OperationOption{
+			Name:       "IfNoneMatch",
+			Type:       "string",
+			HeaderName: &ifNoneMatch,
+		})
+	}
+
+	return output
+}
+
+// hasOptionsOfKind returns whether any of the options are sent as headers (when `headers` is true)
+// or query string parameters (when `headers` is false)
+func hasOptionsOfKind(options []models.OperationOption, headers bool) bool {
+	for _, option := range options {
+		if headers && option.HeaderName != nil {
+			return true
+		}
+		if !headers && option.QueryStringName != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t ModelsTemplater) patchOperationTypes(input models.OperationMetaData, typeName string) map[string]string {
 	structName := fmt.Sprintf("%s%sInput", input.Name, typeName)
-	return map[string]string{
+	output := map[string]string{
 		structName: fmt.Sprintf(`type %s struct {
 	// TODO: implementation
 	// TODO: notably here all fields need to be 'omitempty'
 }`, structName),
 	}
+	t.longRunningOperationResultTypes(input, typeName, output)
+	return output
+}
+
+// longRunningOperationResultTypes adds the model for the final result of a long-running operation
+// with a response body, which is retrieved once the operation has completed
+func (t ModelsTemplater) longRunningOperationResultTypes(input models.OperationMetaData, typeName string, output map[string]string) {
+	if !input.LongRunningOperation || !input.HasResponseBody {
+		return
+	}
+
+	// which is the resource model for `PATCH` and `PUT` operations, see requiresResourceModel
+	if returnsResourceModelWhenCompleted(input.Method, input.LongRunningOperation, input.HasResponseBody) {
+		return
+	}
+
+	structName := fmt.Sprintf("%s%s", input.Name, typeName)
+	output[structName] = fmt.Sprintf(`type %s struct {
+	// TODO: implementation
+}`, structName)
+}
+
+func (t ModelsTemplater) postOperationTypes(input models.OperationMetaData, typeName string) map[string]string {
+	output := make(map[string]string, 0)
+
+	if input.HasRequestBody {
+		inputStructName := fmt.Sprintf("%s%sInput", input.Name, typeName)
+		output[inputStructName] = fmt.Sprintf(`type %s struct {
+	// TODO: implementation
+}`, inputStructName)
+	}
+
+	if input.LongRunningOperation {
+		t.longRunningOperationResultTypes(input, typeName, output)
+		return output
+	}
+
+	if input.HasResponseBody {
+		structName := fmt.Sprintf("%s%s", input.Name, typeName)
+		wrapperStructName := fmt.Sprintf("%sResponse", structName)
+		output[structName] = fmt.Sprintf(`type %s struct {
+	// TODO: implementation
+}`, structName)
+		output[wrapperStructName] = fmt.Sprintf(`type %[1]s struct {
+	HttpResponse *http.Response
+	Model        *%[2]s
+}`, wrapperStructName, structName)
+	}
+
+	return output
 }
 
 func (t ModelsTemplater) putOperationTypes(input models.OperationMetaData, typeName string) map[string]string {
 	structName := fmt.Sprintf("%s%sInput", input.Name, typeName)
-	return map[string]string{
+	output := map[string]string{
 		structName: fmt.Sprintf(`type %s struct {
 	// TODO: implementation
 }`, structName),
 	}
+	t.longRunningOperationResultTypes(input, typeName, output)
+	return output
 }
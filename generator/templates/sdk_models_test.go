@@ -0,0 +1,121 @@
+package templates
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/generator/models"
+	"github.com/tombuildsstuff/pandora/generator/utils"
+)
+
+func TestListOperationsUseTheResourceModel(t *testing.T) {
+	operations := []models.OperationMetaData{
+		{
+			Name:     "List",
+			Method:   http.MethodGet,
+			Pageable: true,
+			ExpectedStatusCodes: []int{
+				200,
+			},
+		},
+	}
+
+	// the resource model is output even when there's no Get operation
+	actual, err := NewModelsTemplater("example", "Namespace", operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "type GetNamespace struct {") {
+		t.Fatalf("expected the models to contain the resource model but got `%s`", *actual)
+	}
+	if strings.Contains(*actual, "type ListNamespace struct {") {
+		t.Fatalf("expected the models not to contain a model for the list operation but got `%s`", *actual)
+	}
+
+	actual, err = NewClientTemplater("example", "Namespace", "2018-01-01", nil, operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "*sdk.Pager[GetNamespace]") {
+		t.Fatalf("expected the list method to return a Pager for the resource model but got `%s`", *actual)
+	}
+}
+
+func TestListOperationsWithinTheSubscription(t *testing.T) {
+	operations := []models.OperationMetaData{
+		{
+			Name:     "List",
+			Method:   http.MethodGet,
+			Pageable: true,
+			ExpectedStatusCodes: []int{
+				200,
+			},
+			ResourceIdName: utils.String("sdk.SubscriptionID"),
+			UriSuffix:      utils.String("/providers/Microsoft.EventHub/namespaces"),
+		},
+	}
+
+	// the URI is built from the Subscription the client is configured for, rather than a Resource ID
+	actual, err := NewClientTemplater("example", "Namespace", "2018-01-01", nil, operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "List(options ListNamespaceOptions) *sdk.Pager[GetNamespace]") {
+		t.Fatalf("expected the list method not to accept a Resource ID but got `%s`", *actual)
+	}
+	if !strings.Contains(*actual, `sdk.BuildResourceManagerURIWithSuffix(sdk.NewSubscriptionID(), "/providers/Microsoft.EventHub/namespaces", client.subscriptionId, client.apiVersion)`) {
+		t.Fatalf("expected the URI to be built from the client's Subscription but got `%s`", *actual)
+	}
+}
+
+func TestLongRunningPutOperationsUseTheResourceModel(t *testing.T) {
+	operations := []models.OperationMetaData{
+		{
+			Name:                 "Create",
+			Method:               http.MethodPut,
+			LongRunningOperation: true,
+			HasResponseBody:      true,
+			ExpectedStatusCodes: []int{
+				200,
+			},
+		},
+	}
+
+	actual, err := NewModelsTemplater("example", "Namespace", operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "type GetNamespace struct {") || strings.Contains(*actual, "type CreateNamespace struct {") {
+		t.Fatalf("expected the models to contain only the resource model but got `%s`", *actual)
+	}
+
+	actual, err = NewClientTemplater("example", "Namespace", "2018-01-01", nil, operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "*sdk.ResultPoller[GetNamespace]") {
+		t.Fatalf("expected the method to return a ResultPoller for the resource model but got `%s`", *actual)
+	}
+}
+
+func TestGetOperationResponseUsesTheTypeName(t *testing.T) {
+	operations := []models.OperationMetaData{
+		{
+			Name:   "Get",
+			Method: http.MethodGet,
+			ExpectedStatusCodes: []int{
+				200,
+			},
+		},
+	}
+
+	// the Get method assigns the model to a field named after the type
+	actual, err := NewModelsTemplater("example", "Namespace", operations).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(*actual, "Namespace    *GetNamespace") {
+		t.Fatalf("expected the response to contain the field `Namespace` but got `%s`", *actual)
+	}
+}
@@ -5,32 +5,57 @@ import (
 	"strings"
 
 	"github.com/tombuildsstuff/pandora/generator/utils"
+	"github.com/tombuildsstuff/pandora/sdk"
 )
 
 type ResourceIDTemplate struct {
-	packageName            string
-	typeName               string
-	resourceIdFormatString string
-	resourceIdSegments     []string
+	packageName string
+	typeName    string
+	segments    []sdk.Segment
 }
 
-func NewResourceIDTemplate(packageName, typeName, resourceIdFormat string, resourceIdSegments []string) ResourceIDTemplate {
+func NewResourceIDTemplate(packageName, typeName string, segments []sdk.Segment) ResourceIDTemplate {
 	return ResourceIDTemplate{
-		packageName:            packageName,
-		typeName:               typeName,
-		resourceIdFormatString: resourceIdFormat,
-		resourceIdSegments:     resourceIdSegments,
+		packageName: packageName,
+		typeName:    typeName,
+		segments:    segments,
 	}
 }
 
 func (t ResourceIDTemplate) Build() (*string, error) {
+	if len(t.segments) == 0 {
+		return nil, fmt.Errorf("the Resource ID %q has no segments", t.typeName)
+	}
+	for _, segment := range t.segments {
+		isFixed := segment.Type == sdk.ResourceProviderSegmentType || segment.Type == sdk.StaticSegmentType
+		if isFixed && segment.FixedValue == nil {
+			return nil, fmt.Errorf("the %s segment %q for the Resource ID %q has no fixed value", segment.Type, segment.Name, t.typeName)
+		}
+	}
+
 	structFields := t.fields("\t")
+	idPrologue := t.subscriptionIdOverride("\t")
 	arguments := t.arguments()
 	constructorFields := t.constructorFieldAssignment("\t\t")
-	fieldArguments := t.fieldArguments("id.")
+	formatString, formatArguments := t.formatStringAndArguments("id.")
+	idSignature := "ID(subscriptionId string) string"
+	if t.isScoped() {
+		idSignature = "ID() string"
+	}
+	segments, err := t.segmentDefinitions("\t\t")
+	if err != nil {
+		return nil, fmt.Errorf("building segments: %+v", err)
+	}
+	components := t.descriptionComponents("\t\t")
+	parsedFields := t.parsedFieldAssignment("\t\t")
 	template := fmt.Sprintf(`package %[1]s
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 type %[2]sID struct {
 %[3]s
@@ -42,21 +67,127 @@ func New%[2]sID(%[4]s) %[2]sID {
 	}
 }
 
-func (id %[2]sID) ID(subscriptionId string) string {
-	return fmt.Sprintf("%[6]s", subscriptionId, %[7]s)
-}`, t.packageName, t.typeName, structFields, arguments, constructorFields, t.resourceIdFormatString, fieldArguments)
+func (id %[2]sID) %[12]s {
+%[13]s	return fmt.Sprintf(%[6]q, %[7]s)
+}
+
+// Segments returns a slice of Resource ID Segments which comprise this %[2]s ID
+func (id %[2]sID) Segments() []sdk.Segment {
+	return []sdk.Segment{
+%[8]s,
+	}
+}
+
+// String returns a human-readable description of this %[2]s ID
+func (id %[2]sID) String() string {
+	components := []string{
+%[9]s,
+	}
+	return fmt.Sprintf("%[10]s (%%s)", strings.Join(components, "\n"))
+}
+
+// Parse%[2]sID parses 'input' into a %[2]sID
+func Parse%[2]sID(input string) (*%[2]sID, error) {
+	return parse%[2]sID(input, false)
+}
+
+// Parse%[2]sIDInsensitively parses 'input' case-insensitively into a %[2]sID
+// note: this method should only be used for API response data and not user input
+func Parse%[2]sIDInsensitively(input string) (*%[2]sID, error) {
+	return parse%[2]sID(input, true)
+}
+
+func parse%[2]sID(input string, insensitively bool) (*%[2]sID, error) {
+	parser := sdk.NewResourceIDParser(%[2]sID{}.Segments())
+	parsed, err := parser.Parse(input, insensitively)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %%q as a %[2]s ID: %%+v", input, err)
+	}
+
+	id := %[2]sID{
+%[11]s,
+	}
+	return &id, nil
+}`, t.packageName, t.typeName, structFields, arguments, constructorFields, formatString, formatArguments, *segments, components, utils.HumanizeName(t.typeName), parsedFields, idSignature, idPrologue)
 	return &template, nil
 }
 
-// properties returns the segments formatted for used as the struct properties for an ID Parser
-func (t ResourceIDTemplate) fields(indent string) string {
-	output := make([]string, 0)
-	for _, k := range t.resourceIdSegments {
-		if strings.EqualFold("subscriptionId", k) {
+// isScoped returns whether this is a Scoped Resource ID, which isn't within the Subscription the client is configured
+// for - such as a Management Group, a tenant-level resource or an extension resource within an arbitrary scope
+func (t ResourceIDTemplate) isScoped() bool {
+	hasSubscriptionId := false
+	for _, segment := range t.segments {
+		if segment.Type == sdk.ScopeSegmentType {
+			return true
+		}
+		if segment.Type == sdk.SubscriptionIdSegmentType {
+			hasSubscriptionId = true
+		}
+	}
+
+	return !hasSubscriptionId
+}
+
+// fieldSegments returns the segments which are exposed as fields on the ID struct
+func (t ResourceIDTemplate) fieldSegments() []sdk.Segment {
+	output := make([]sdk.Segment, 0)
+	for _, segment := range t.segments {
+		switch segment.Type {
+		case sdk.ResourceGroupSegmentType, sdk.ScopeSegmentType, sdk.SubscriptionIdSegmentType, sdk.UserSpecifiedSegmentType:
+			output = append(output, segment)
+		}
+	}
+	return output
+}
+
+// constructorSegments returns the segments which are specified when constructing the ID - notably the Subscription ID
+// isn't unless this is a Scoped Resource ID, since otherwise this defaults to the Subscription the client is configured for
+func (t ResourceIDTemplate) constructorSegments() []sdk.Segment {
+	output := make([]sdk.Segment, 0)
+	for _, segment := range t.fieldSegments() {
+		if segment.Type == sdk.SubscriptionIdSegmentType && !t.isScoped() {
 			continue
 		}
+		output = append(output, segment)
+	}
+	return output
+}
+
+// subscriptionIdSegment returns the Subscription ID segment which overrides the Subscription the client is configured
+// for when set (for example when the ID has been parsed) - which is nil for Scoped Resource IDs
+func (t ResourceIDTemplate) subscriptionIdSegment() *sdk.Segment {
+	if t.isScoped() {
+		return nil
+	}
+
+	for _, segment := range t.segments {
+		if segment.Type == sdk.SubscriptionIdSegmentType {
+			return &segment
+		}
+	}
+	return nil
+}
+
+// subscriptionIdOverride returns the statements used to build the Resource ID using the Subscription ID field when set
+func (t ResourceIDTemplate) subscriptionIdOverride(indent string) string {
+	segment := t.subscriptionIdSegment()
+	if segment == nil {
+		return ""
+	}
 
-		normalized := utils.NormalizePropertyName(k)
+	normalized := utils.NormalizePropertyName(segment.Name)
+	return fmt.Sprintf("%[1]sif id.%[2]s != \"\" {\n%[1]s\tsubscriptionId = id.%[2]s\n%[1]s}\n", indent, normalized)
+}
+
+// fields returns the segments formatted for used as the struct properties for an ID Parser
+func (t ResourceIDTemplate) fields(indent string) string {
+	output := make([]string, 0)
+	subscriptionIdSegment := t.subscriptionIdSegment()
+	for _, segment := range t.fieldSegments() {
+		normalized := utils.NormalizePropertyName(segment.Name)
+		if subscriptionIdSegment != nil && segment.Name == subscriptionIdSegment.Name {
+			output = append(output, fmt.Sprintf("%[1]s// %[2]s is the Subscription this resource is within, which defaults to the\n%[1]s// Subscription the client is configured for when empty", indent, normalized))
+		}
 		formatted := fmt.Sprintf("%s%s\tstring", indent, normalized)
 		output = append(output, formatted)
 	}
@@ -67,12 +198,8 @@ func (t ResourceIDTemplate) fields(indent string) string {
 // arguments returns the segments formatted for used as the arguments for an ID Parser
 func (t ResourceIDTemplate) arguments() string {
 	args := make([]string, 0)
-	for _, k := range t.resourceIdSegments {
-		if strings.EqualFold("subscriptionId", k) {
-			continue
-		}
-
-		args = append(args, fmt.Sprintf("%s string", k))
+	for _, segment := range t.constructorSegments() {
+		args = append(args, fmt.Sprintf("%s string", segment.Name))
 	}
 
 	return strings.Join(args, ", ")
@@ -81,32 +208,111 @@ func (t ResourceIDTemplate) arguments() string {
 func (t ResourceIDTemplate) constructorFieldAssignment(indent string) string {
 	output := make([]string, 0)
 
-	for _, k := range t.resourceIdSegments {
-		if strings.EqualFold("subscriptionId", k) {
+	for _, segment := range t.constructorSegments() {
+		normalized := utils.NormalizePropertyName(segment.Name)
+		formatted := fmt.Sprintf("%s%s: %s", indent, normalized, segment.Name)
+		output = append(output, formatted)
+	}
+
+	return strings.Join(output, ",\n")
+}
+
+// formatStringAndArguments returns the format string and arguments used to format the Resource ID
+func (t ResourceIDTemplate) formatStringAndArguments(prefix string) (string, string) {
+	components := make([]string, 0)
+	arguments := make([]string, 0)
+
+	for _, segment := range t.segments {
+		switch segment.Type {
+		case sdk.ResourceProviderSegmentType, sdk.StaticSegmentType:
+			components = append(components, fmt.Sprintf("/%s", *segment.FixedValue))
+
+		case sdk.SubscriptionIdSegmentType:
+			components = append(components, "/%s")
+			if t.isScoped() {
+				normalized := utils.NormalizePropertyName(segment.Name)
+				arguments = append(arguments, fmt.Sprintf("%s%s", prefix, normalized))
+				continue
+			}
+			arguments = append(arguments, "subscriptionId")
+
+		case sdk.ScopeSegmentType:
+			// e.g. "sdk.NormalizeScope(id.Scope)" - which includes the leading slash, and is empty for the tenant-root scope
+			components = append(components, "%s")
+			normalized := utils.NormalizePropertyName(segment.Name)
+			arguments = append(arguments, fmt.Sprintf("sdk.NormalizeScope(%s%s)", prefix, normalized))
 			continue
+
+		default:
+			// e.g. "id.Name"
+			components = append(components, "/%s")
+			normalized := utils.NormalizePropertyName(segment.Name)
+			arguments = append(arguments, fmt.Sprintf("%s%s", prefix, normalized))
 		}
+	}
 
-		normalized := utils.NormalizePropertyName(k)
-		formatted := fmt.Sprintf("%s%s: %s", indent, normalized, k)
+	formatString := strings.Join(components, "")
+	if formatString == "" {
+		formatString = "/"
+	}
+	return formatString, strings.Join(arguments, ", ")
+}
+
+func (t ResourceIDTemplate) segmentDefinitions(indent string) (*string, error) {
+	output := make([]string, 0)
+
+	for _, segment := range t.segments {
+		var formatted string
+		switch segment.Type {
+		case sdk.ResourceGroupSegmentType:
+			formatted = fmt.Sprintf("sdk.ResourceGroupSegment(%q, %q)", segment.Name, segment.ExampleValue)
+
+		case sdk.ResourceProviderSegmentType:
+			formatted = fmt.Sprintf("sdk.ResourceProviderSegment(%q, %q)", segment.Name, *segment.FixedValue)
+
+		case sdk.ScopeSegmentType:
+			formatted = fmt.Sprintf("sdk.ScopeSegment(%q, %q)", segment.Name, segment.ExampleValue)
+
+		case sdk.StaticSegmentType:
+			formatted = fmt.Sprintf("sdk.StaticSegment(%q, %q)", segment.Name, *segment.FixedValue)
+
+		case sdk.SubscriptionIdSegmentType:
+			formatted = fmt.Sprintf("sdk.SubscriptionIdSegment(%q, %q)", segment.Name, segment.ExampleValue)
+
+		case sdk.UserSpecifiedSegmentType:
+			formatted = fmt.Sprintf("sdk.UserSpecifiedSegment(%q, %q)", segment.Name, segment.ExampleValue)
+
+		default:
+			return nil, fmt.Errorf("unsupported segment type %q for %q", segment.Type, segment.Name)
+		}
+
+		output = append(output, fmt.Sprintf("%s%s", indent, formatted))
+	}
+
+	result := strings.Join(output, ",\n")
+	return &result, nil
+}
+
+func (t ResourceIDTemplate) descriptionComponents(indent string) string {
+	output := make([]string, 0)
+
+	for _, segment := range t.constructorSegments() {
+		normalized := utils.NormalizePropertyName(segment.Name)
+		formatted := fmt.Sprintf("%sfmt.Sprintf(\"%s %%q\", id.%s)", indent, utils.HumanizeName(segment.Name), normalized)
 		output = append(output, formatted)
 	}
 
 	return strings.Join(output, ",\n")
 }
 
-func (t ResourceIDTemplate) fieldArguments(prefix string) string {
+func (t ResourceIDTemplate) parsedFieldAssignment(indent string) string {
 	output := make([]string, 0)
 
-	for _, k := range t.resourceIdSegments {
-		if strings.EqualFold("subscriptionId", k) {
-			continue
-		}
-
-		// e.g. "id.Name"
-		normalized := utils.NormalizePropertyName(k)
-		formatted := fmt.Sprintf("%s%s", prefix, normalized)
+	for _, segment := range t.fieldSegments() {
+		normalized := utils.NormalizePropertyName(segment.Name)
+		formatted := fmt.Sprintf("%s%s: parsed.Parsed[%q]", indent, normalized, segment.Name)
 		output = append(output, formatted)
 	}
 
-	return strings.Join(output, ", ")
+	return strings.Join(output, ",\n")
 }
@@ -1,13 +1,26 @@
 package templates
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 func TestEventHubNamespaceResourceID(t *testing.T) {
 	expected := `package example
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 type EventHubNamespaceID struct {
+	// SubscriptionId is the Subscription this resource is within, which defaults to the
+	// Subscription the client is configured for when empty
+	SubscriptionId	string
 	ResourceGroup	string
 	Namespace	string
 }
@@ -20,15 +33,72 @@ func NewEventHubNamespaceID(resourceGroup string, namespace string) EventHubName
 }
 
 func (id EventHubNamespaceID) ID(subscriptionId string) string {
-	return fmt.Sprintf("/subscriptions/{subscriptionId}/resourceGroups/{name}/providers/Microsoft.EventHub/namespaces/{namespace}", subscriptionId, id.ResourceGroup, id.Namespace)
+	if id.SubscriptionId != "" {
+		subscriptionId = id.SubscriptionId
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.EventHub/namespaces/%s", subscriptionId, id.ResourceGroup, id.Namespace)
+}
+
+// Segments returns a slice of Resource ID Segments which comprise this EventHubNamespace ID
+func (id EventHubNamespaceID) Segments() []sdk.Segment {
+	return []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.ResourceGroupSegment("resourceGroup", "example-resources"),
+		sdk.StaticSegment("staticProviders", "providers"),
+		sdk.ResourceProviderSegment("staticMicrosoftEventHub", "Microsoft.EventHub"),
+		sdk.StaticSegment("staticNamespaces", "namespaces"),
+		sdk.UserSpecifiedSegment("namespace", "example-namespace"),
+	}
+}
+
+// String returns a human-readable description of this EventHubNamespace ID
+func (id EventHubNamespaceID) String() string {
+	components := []string{
+		fmt.Sprintf("Resource Group %q", id.ResourceGroup),
+		fmt.Sprintf("Namespace %q", id.Namespace),
+	}
+	return fmt.Sprintf("Event Hub Namespace (%s)", strings.Join(components, "\n"))
+}
+
+// ParseEventHubNamespaceID parses 'input' into a EventHubNamespaceID
+func ParseEventHubNamespaceID(input string) (*EventHubNamespaceID, error) {
+	return parseEventHubNamespaceID(input, false)
+}
+
+// ParseEventHubNamespaceIDInsensitively parses 'input' case-insensitively into a EventHubNamespaceID
+// note: this method should only be used for API response data and not user input
+func ParseEventHubNamespaceIDInsensitively(input string) (*EventHubNamespaceID, error) {
+	return parseEventHubNamespaceID(input, true)
+}
+
+func parseEventHubNamespaceID(input string, insensitively bool) (*EventHubNamespaceID, error) {
+	parser := sdk.NewResourceIDParser(EventHubNamespaceID{}.Segments())
+	parsed, err := parser.Parse(input, insensitively)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q as a EventHubNamespace ID: %+v", input, err)
+	}
+
+	id := EventHubNamespaceID{
+		SubscriptionId: parsed.Parsed["subscriptionId"],
+		ResourceGroup: parsed.Parsed["resourceGroup"],
+		Namespace: parsed.Parsed["namespace"],
+	}
+	return &id, nil
 }`
 
-	formatString := "/subscriptions/{subscriptionId}/resourceGroups/{name}/providers/Microsoft.EventHub/namespaces/{namespace}"
-	segments := []string{
-		"resourceGroup",
-		"namespace",
+	segments := []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.ResourceGroupSegment("resourceGroup", "example-resources"),
+		sdk.StaticSegment("staticProviders", "providers"),
+		sdk.ResourceProviderSegment("staticMicrosoftEventHub", "Microsoft.EventHub"),
+		sdk.StaticSegment("staticNamespaces", "namespaces"),
+		sdk.UserSpecifiedSegment("namespace", "example-namespace"),
 	}
-	template := NewResourceIDTemplate("example", "EventHubNamespace", formatString, segments)
+	template := NewResourceIDTemplate("example", "EventHubNamespace", segments)
 	actual, err := template.Build()
 	if err != nil {
 		t.Fatal(err)
@@ -42,9 +112,17 @@ func (id EventHubNamespaceID) ID(subscriptionId string) string {
 func TestResourceGroupResourceID(t *testing.T) {
 	expected := `package dora
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 type ResourceGroupID struct {
+	// SubscriptionId is the Subscription this resource is within, which defaults to the
+	// Subscription the client is configured for when empty
+	SubscriptionId	string
 	Name	string
 }
 
@@ -55,14 +133,62 @@ func NewResourceGroupID(name string) ResourceGroupID {
 }
 
 func (id ResourceGroupID) ID(subscriptionId string) string {
-	return fmt.Sprintf("/subscriptions/{subscriptionId}/resourceGroups/{name}", subscriptionId, id.Name)
+	if id.SubscriptionId != "" {
+		subscriptionId = id.SubscriptionId
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionId, id.Name)
+}
+
+// Segments returns a slice of Resource ID Segments which comprise this ResourceGroup ID
+func (id ResourceGroupID) Segments() []sdk.Segment {
+	return []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.UserSpecifiedSegment("name", "example-resources"),
+	}
+}
+
+// String returns a human-readable description of this ResourceGroup ID
+func (id ResourceGroupID) String() string {
+	components := []string{
+		fmt.Sprintf("Name %q", id.Name),
+	}
+	return fmt.Sprintf("Resource Group (%s)", strings.Join(components, "\n"))
+}
+
+// ParseResourceGroupID parses 'input' into a ResourceGroupID
+func ParseResourceGroupID(input string) (*ResourceGroupID, error) {
+	return parseResourceGroupID(input, false)
+}
+
+// ParseResourceGroupIDInsensitively parses 'input' case-insensitively into a ResourceGroupID
+// note: this method should only be used for API response data and not user input
+func ParseResourceGroupIDInsensitively(input string) (*ResourceGroupID, error) {
+	return parseResourceGroupID(input, true)
+}
+
+func parseResourceGroupID(input string, insensitively bool) (*ResourceGroupID, error) {
+	parser := sdk.NewResourceIDParser(ResourceGroupID{}.Segments())
+	parsed, err := parser.Parse(input, insensitively)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q as a ResourceGroup ID: %+v", input, err)
+	}
+
+	id := ResourceGroupID{
+		SubscriptionId: parsed.Parsed["subscriptionId"],
+		Name: parsed.Parsed["name"],
+	}
+	return &id, nil
 }`
 
-	formatString := "/subscriptions/{subscriptionId}/resourceGroups/{name}"
-	segments := []string{
-		"name",
+	segments := []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.UserSpecifiedSegment("name", "example-resources"),
 	}
-	template := NewResourceIDTemplate("dora", "ResourceGroup", formatString, segments)
+	template := NewResourceIDTemplate("dora", "ResourceGroup", segments)
 	actual, err := template.Build()
 	if err != nil {
 		t.Fatal(err)
@@ -72,3 +198,68 @@ func (id ResourceGroupID) ID(subscriptionId string) string {
 		t.Fatalf("Expected `%s` but got `%s`", expected, *actual)
 	}
 }
+
+func TestResourceIDMissingFixedValue(t *testing.T) {
+	segments := []sdk.Segment{
+		{
+			Name: "staticSubscriptions",
+			Type: sdk.StaticSegmentType,
+		},
+	}
+	template := NewResourceIDTemplate("dora", "ResourceGroup", segments)
+	if _, err := template.Build(); err == nil {
+		t.Fatal("expected an error when a static segment has no fixed value")
+	}
+}
+
+func TestScopedResourceID(t *testing.T) {
+	testData := []struct {
+		typeName string
+		segments []sdk.Segment
+		expected []string
+	}{
+		{
+			// extension resources can be within any scope
+			typeName: "Lock",
+			segments: []sdk.Segment{
+				sdk.ScopeSegment("scope", "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/example-resources"),
+				sdk.StaticSegment("staticProviders", "providers"),
+				sdk.ResourceProviderSegment("staticMicrosoftAuthorization", "Microsoft.Authorization"),
+				sdk.StaticSegment("staticLocks", "locks"),
+				sdk.UserSpecifiedSegment("name", "example-lock"),
+			},
+			expected: []string{
+				"func NewLockID(scope string, name string) LockID {",
+				"func (id LockID) ID() string {",
+				`return fmt.Sprintf("%s/providers/Microsoft.Authorization/locks/%s", sdk.NormalizeScope(id.Scope), id.Name)`,
+			},
+		},
+		{
+			// tenant-level resources aren't within a subscription
+			typeName: "ManagementGroup",
+			segments: []sdk.Segment{
+				sdk.StaticSegment("staticProviders", "providers"),
+				sdk.ResourceProviderSegment("staticMicrosoftManagement", "Microsoft.Management"),
+				sdk.StaticSegment("staticManagementGroups", "managementGroups"),
+				sdk.UserSpecifiedSegment("name", "example-group"),
+			},
+			expected: []string{
+				"func NewManagementGroupID(name string) ManagementGroupID {",
+				"func (id ManagementGroupID) ID() string {",
+				`return fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s", id.Name)`,
+			},
+		},
+	}
+	for _, v := range testData {
+		actual, err := NewResourceIDTemplate("example", v.typeName, v.segments).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expected := range v.expected {
+			if !strings.Contains(*actual, expected) {
+				t.Fatalf("expected the %s ID to contain `%s` but got `%s`", v.typeName, expected, *actual)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"time"
 )
 
@@ -32,7 +33,7 @@ func (f GolangCodeFormatter) Format(input string) (*string, error) {
 
 func (f GolangCodeFormatter) randomFilePath() string {
 	time := time.Now().Unix()
-	return fmt.Sprintf("%stemp-%d.go", os.TempDir(), time)
+	return filepath.Join(os.TempDir(), fmt.Sprintf("temp-%d.go", time))
 }
 
 func (f GolangCodeFormatter) runGoFmt(filePath string) error {
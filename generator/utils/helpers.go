@@ -2,8 +2,31 @@ package utils
 
 import (
 	"strings"
+	"unicode"
 )
 
 func NormalizePropertyName(input string) string {
 	return strings.Title(input)
 }
+
+func String(input string) *string {
+	return &input
+}
+
+// HumanizeName splits a camel-cased name into words, e.g. `resourceGroupName` becomes `Resource Group Name`
+func HumanizeName(input string) string {
+	words := make([]string, 0)
+	current := make([]rune, 0)
+	for _, r := range input {
+		if unicode.IsUpper(r) && len(current) > 0 {
+			words = append(words, string(current))
+			current = make([]rune, 0)
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+
+	return NormalizePropertyName(strings.Join(words, " "))
+}
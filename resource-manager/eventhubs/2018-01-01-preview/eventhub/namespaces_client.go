@@ -6,7 +6,7 @@ import (
 	"net/http"
 
 	"github.com/tombuildsstuff/pandora/sdk"
-	"github.com/tombuildsstuff/pandora/sdk/endpoints"
+	"github.com/tombuildsstuff/pandora/sdk/environments"
 )
 
 type NamespacesClient struct {
@@ -16,36 +16,46 @@ type NamespacesClient struct {
 }
 
 func NewNamespacesClient(subscriptionId string, authorizer sdk.Authorizer) NamespacesClient {
-	return NewNamespacesClientWithBaseURI(endpoints.DefaultManagementEndpoint, subscriptionId, authorizer)
+	return NewNamespacesClientWithEnvironment(environments.Default, subscriptionId, authorizer)
 }
 
-func NewNamespacesClientWithBaseURI(endpoint string, subscriptionId string, authorizer sdk.Authorizer) NamespacesClient {
+func NewNamespacesClientWithEnvironment(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer) NamespacesClient {
+	return NewNamespacesClientWithOptions(environment, subscriptionId, authorizer, sdk.ClientOptions{})
+}
+
+func NewNamespacesClientWithOptions(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer, options sdk.ClientOptions) NamespacesClient {
 	return NamespacesClient{
 		apiVersion:     "2018-01-01-preview",
-		baseClient:     sdk.DefaultBaseClient(endpoint, authorizer),
+		baseClient:     sdk.NewBaseClient(environment, authorizer, options),
 		subscriptionId: subscriptionId,
 	}
 }
 
-func (client NamespacesClient) Create(ctx context.Context, id NamespaceID, input CreateNamespaceInput) (sdk.Poller, error) {
-	uri := sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion)
+func (client NamespacesClient) Create(ctx context.Context, id NamespaceID, input CreateNamespaceInput, options CreateNamespaceOptions) (*sdk.ResultPoller[GetNamespace], error) {
 	req := sdk.PutHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
-			http.StatusOK, // new
+			http.StatusOK, // ok
 		},
-		Uri: uri,
+		Headers: options.toHeaders(),
+		Uri:     sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+	}
+
+	poller, err := client.baseClient.PutJsonThenPoll(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
-	return client.baseClient.PutJsonThenPoll(ctx, req)
+	return sdk.NewResultPoller[GetNamespace](poller, &client.baseClient, sdk.FinalStateViaDefault), nil
 }
 
-func (client NamespacesClient) Delete(ctx context.Context, id NamespaceID) (sdk.Poller, error) {
+func (client NamespacesClient) Delete(ctx context.Context, id NamespaceID, options DeleteNamespaceOptions) (sdk.Poller, error) {
 	req := sdk.DeleteHttpRequestInput{
 		ExpectedStatusCodes: []int{
-			http.StatusAccepted, // delete accepted
+			http.StatusAccepted, // deletion accepted
 		},
-		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
+		Headers: options.toHeaders(),
+		Uri:     sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
 
 	return client.baseClient.DeleteThenPoll(ctx, req)
@@ -54,7 +64,7 @@ func (client NamespacesClient) Delete(ctx context.Context, id NamespaceID) (sdk.
 func (client NamespacesClient) Get(ctx context.Context, id NamespaceID) (*GetNamespaceResponse, error) {
 	req := sdk.GetHttpRequestInput{
 		ExpectedStatusCodes: []int{
-			http.StatusOK, // Exists
+			http.StatusOK, // ok
 		},
 		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
@@ -62,16 +72,43 @@ func (client NamespacesClient) Get(ctx context.Context, id NamespaceID) (*GetNam
 	var out GetNamespace
 	resp, err := client.baseClient.GetJson(ctx, req, &out)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %+v", err)
+		return nil, fmt.Errorf("sending Request: %w", err)
 	}
 
 	result := GetNamespaceResponse{
+		ETag:         sdk.ETagFromResponse(resp),
 		HttpResponse: resp,
 		Namespace:    &out,
 	}
 	return &result, nil
 }
 
+func (client NamespacesClient) List(options ListNamespaceOptions) *sdk.Pager[GetNamespace] {
+	req := sdk.ListHttpRequestInput{
+		ExpectedStatusCodes: []int{
+			http.StatusOK, // ok
+		},
+		Filter: options.Filter,
+		Top:    options.Top,
+		Uri:    sdk.BuildResourceManagerURIWithSuffix(sdk.NewSubscriptionID(), "/providers/Microsoft.EventHub/namespaces", client.subscriptionId, client.apiVersion),
+	}
+
+	return sdk.NewPager[GetNamespace](client.baseClient, req)
+}
+
+func (client NamespacesClient) ListByResourceGroup(id sdk.ResourceGroupID, options ListByResourceGroupNamespaceOptions) *sdk.Pager[GetNamespace] {
+	req := sdk.ListHttpRequestInput{
+		ExpectedStatusCodes: []int{
+			http.StatusOK, // ok
+		},
+		Filter: options.Filter,
+		Top:    options.Top,
+		Uri:    sdk.BuildResourceManagerURIWithSuffix(id, "/providers/Microsoft.EventHub/namespaces", client.subscriptionId, client.apiVersion),
+	}
+
+	return sdk.NewPager[GetNamespace](client.baseClient, req)
+}
+
 func (client NamespacesClient) MetaData() sdk.ClientMetaData {
 	resourceProvider := "Microsoft.EventHub"
 	return sdk.ClientMetaData{
@@ -1,19 +1,79 @@
 package eventhub
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 type NamespaceID struct {
-	Name          string
-	ResourceGroup string
+	// SubscriptionId is the Subscription this resource is within, which defaults to the
+	// Subscription the client is configured for when empty
+	SubscriptionId string
+	ResourceGroup  string
+	Name           string
 }
 
-func NewNamespaceID(resourceGroup, name string) NamespaceID {
+func NewNamespaceID(resourceGroup string, name string) NamespaceID {
 	return NamespaceID{
-		Name:          name,
 		ResourceGroup: resourceGroup,
+		Name:          name,
 	}
 }
 
 func (id NamespaceID) ID(subscriptionId string) string {
+	if id.SubscriptionId != "" {
+		subscriptionId = id.SubscriptionId
+	}
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.EventHub/namespaces/%s", subscriptionId, id.ResourceGroup, id.Name)
 }
+
+// Segments returns a slice of Resource ID Segments which comprise this Namespace ID
+func (id NamespaceID) Segments() []sdk.Segment {
+	return []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.ResourceGroupSegment("resourceGroup", "example-resources"),
+		sdk.StaticSegment("staticProviders", "providers"),
+		sdk.ResourceProviderSegment("staticMicrosoftEventHub", "Microsoft.EventHub"),
+		sdk.StaticSegment("staticNamespaces", "namespaces"),
+		sdk.UserSpecifiedSegment("name", "example-namespace"),
+	}
+}
+
+// String returns a human-readable description of this Namespace ID
+func (id NamespaceID) String() string {
+	components := []string{
+		fmt.Sprintf("Resource Group %q", id.ResourceGroup),
+		fmt.Sprintf("Name %q", id.Name),
+	}
+	return fmt.Sprintf("Namespace (%s)", strings.Join(components, "\n"))
+}
+
+// ParseNamespaceID parses 'input' into a NamespaceID
+func ParseNamespaceID(input string) (*NamespaceID, error) {
+	return parseNamespaceID(input, false)
+}
+
+// ParseNamespaceIDInsensitively parses 'input' case-insensitively into a NamespaceID
+// note: this method should only be used for API response data and not user input
+func ParseNamespaceIDInsensitively(input string) (*NamespaceID, error) {
+	return parseNamespaceID(input, true)
+}
+
+func parseNamespaceID(input string, insensitively bool) (*NamespaceID, error) {
+	parser := sdk.NewResourceIDParser(NamespaceID{}.Segments())
+	parsed, err := parser.Parse(input, insensitively)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q as a Namespace ID: %+v", input, err)
+	}
+
+	id := NamespaceID{
+		SubscriptionId: parsed.Parsed["subscriptionId"],
+		ResourceGroup:  parsed.Parsed["resourceGroup"],
+		Name:           parsed.Parsed["name"],
+	}
+	return &id, nil
+}
@@ -1,6 +1,9 @@
 package eventhub
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+)
 
 type SkuName string
 
@@ -27,13 +30,49 @@ type CreateNamespaceProperties struct {
 	ZoneRedundant        bool `json:"zoneRedundant"`
 }
 
+type CreateNamespaceOptions struct {
+	IfMatch     *string
+	IfNoneMatch *string
+}
+
+func (o CreateNamespaceOptions) toHeaders() map[string]string {
+	output := make(map[string]string)
+	if o.IfMatch != nil {
+		output["If-Match"] = fmt.Sprintf("%v", *o.IfMatch)
+	}
+	if o.IfNoneMatch != nil {
+		output["If-None-Match"] = fmt.Sprintf("%v", *o.IfNoneMatch)
+	}
+	return output
+}
+
+type DeleteNamespaceOptions struct {
+	IfMatch     *string
+	IfNoneMatch *string
+}
+
+func (o DeleteNamespaceOptions) toHeaders() map[string]string {
+	output := make(map[string]string)
+	if o.IfMatch != nil {
+		output["If-Match"] = fmt.Sprintf("%v", *o.IfMatch)
+	}
+	if o.IfNoneMatch != nil {
+		output["If-None-Match"] = fmt.Sprintf("%v", *o.IfNoneMatch)
+	}
+	return output
+}
+
 type GetNamespaceResponse struct {
+	// ETag is the current version of the resource, which can be used for conditional requests
+	ETag         *string
 	HttpResponse *http.Response
 	Namespace    *GetNamespace
 }
 
 type GetNamespace struct {
+	ID         string                 `json:"id,omitempty"`
 	Location   string                 `json:"location"`
+	Name       string                 `json:"name,omitempty"`
 	Properties GetNamespaceProperties `json:"properties"`
 	Sku        Sku                    `json:"sku"`
 	Tags       map[string]string      `json:"tags"`
@@ -44,3 +83,13 @@ type GetNamespaceProperties struct {
 	ServiceBusEndpoint   string `json:"serviceBusEndpoint"`
 	ZoneRedundant        bool   `json:"zoneRedundant"`
 }
+
+type ListNamespaceOptions struct {
+	Filter *string
+	Top    *int
+}
+
+type ListByResourceGroupNamespaceOptions struct {
+	Filter *string
+	Top    *int
+}
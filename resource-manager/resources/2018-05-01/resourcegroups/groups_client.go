@@ -6,47 +6,51 @@ import (
 	"net/http"
 
 	"github.com/tombuildsstuff/pandora/sdk"
-	"github.com/tombuildsstuff/pandora/sdk/endpoints"
+	"github.com/tombuildsstuff/pandora/sdk/environments"
 )
 
-type Client struct {
+type ResourceGroupsClient struct {
 	apiVersion     string
 	baseClient     sdk.BaseClient
 	subscriptionId string
 }
 
-func NewClient(subscriptionId string, authorizer sdk.Authorizer) Client {
-	return NewClientWithBaseURI(endpoints.DefaultManagementEndpoint, subscriptionId, authorizer)
+func NewResourceGroupsClient(subscriptionId string, authorizer sdk.Authorizer) ResourceGroupsClient {
+	return NewResourceGroupsClientWithEnvironment(environments.Default, subscriptionId, authorizer)
 }
 
-func NewClientWithBaseURI(endpoint string, subscriptionId string, authorizer sdk.Authorizer) Client {
-	return Client{
+func NewResourceGroupsClientWithEnvironment(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer) ResourceGroupsClient {
+	return NewResourceGroupsClientWithOptions(environment, subscriptionId, authorizer, sdk.ClientOptions{})
+}
+
+func NewResourceGroupsClientWithOptions(environment environments.Environment, subscriptionId string, authorizer sdk.Authorizer, options sdk.ClientOptions) ResourceGroupsClient {
+	return ResourceGroupsClient{
 		apiVersion:     "2018-05-01",
-		baseClient:     sdk.DefaultBaseClient(endpoint, authorizer),
+		baseClient:     sdk.NewBaseClient(environment, authorizer, options),
 		subscriptionId: subscriptionId,
 	}
 }
 
-func (client Client) Create(ctx context.Context, id ResourceGroupID, input CreateResourceGroupInput) error {
+func (client ResourceGroupsClient) Create(ctx context.Context, id ResourceGroupID, input CreateResourceGroupInput) error {
 	req := sdk.PutHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
-			http.StatusOK,      // already exists
-			http.StatusCreated, // new
+			http.StatusOK,      // ok
+			http.StatusCreated, // created
 		},
 		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
 
 	if _, err := client.baseClient.PutJson(ctx, req); err != nil {
-		return fmt.Errorf("sending Request: %+v", err)
+		return fmt.Errorf("sending Request: %w", err)
 	}
 	return nil
 }
 
-func (client Client) Delete(ctx context.Context, id ResourceGroupID) (sdk.Poller, error) {
+func (client ResourceGroupsClient) Delete(ctx context.Context, id ResourceGroupID) (sdk.Poller, error) {
 	req := sdk.DeleteHttpRequestInput{
 		ExpectedStatusCodes: []int{
-			http.StatusAccepted, // delete accepted
+			http.StatusAccepted, // deletion accepted
 		},
 		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
@@ -54,10 +58,32 @@ func (client Client) Delete(ctx context.Context, id ResourceGroupID) (sdk.Poller
 	return client.baseClient.DeleteThenPoll(ctx, req)
 }
 
-func (client Client) Get(ctx context.Context, id ResourceGroupID) (*GetResourceGroupResponse, error) {
+func (client ResourceGroupsClient) ExportTemplate(ctx context.Context, id ResourceGroupID, input ExportTemplateResourceGroupInput) (*ExportTemplateResourceGroupResponse, error) {
+	req := sdk.PostHttpRequestInput{
+		Body: input,
+		ExpectedStatusCodes: []int{
+			http.StatusOK, // ok
+		},
+		Uri: sdk.BuildResourceManagerURIWithSuffix(id, "/exportTemplate", client.subscriptionId, client.apiVersion),
+	}
+
+	var out ExportTemplateResourceGroup
+	resp, err := client.baseClient.PostJson(ctx, req, &out)
+	if err != nil {
+		return nil, fmt.Errorf("sending Request: %w", err)
+	}
+
+	result := ExportTemplateResourceGroupResponse{
+		HttpResponse: resp,
+		Model:        &out,
+	}
+	return &result, nil
+}
+
+func (client ResourceGroupsClient) Get(ctx context.Context, id ResourceGroupID) (*GetResourceGroupResponse, error) {
 	req := sdk.GetHttpRequestInput{
 		ExpectedStatusCodes: []int{
-			http.StatusOK, // Exists
+			http.StatusOK, // ok
 		},
 		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
@@ -65,31 +91,46 @@ func (client Client) Get(ctx context.Context, id ResourceGroupID) (*GetResourceG
 	var out GetResourceGroup
 	resp, err := client.baseClient.GetJson(ctx, req, &out)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %+v", err)
+		return nil, fmt.Errorf("sending Request: %w", err)
 	}
 
 	result := GetResourceGroupResponse{
+		ETag:          sdk.ETagFromResponse(resp),
 		HttpResponse:  resp,
 		ResourceGroup: &out,
 	}
 	return &result, nil
 }
 
-func (client Client) Update(ctx context.Context, id ResourceGroupID, input UpdateResourceGroupInput) error {
+func (client ResourceGroupsClient) List(options ListResourceGroupOptions) *sdk.Pager[GetResourceGroup] {
+	req := sdk.ListHttpRequestInput{
+		ExpectedStatusCodes: []int{
+			http.StatusOK, // ok
+		},
+		Filter: options.Filter,
+		Top:    options.Top,
+		Uri:    sdk.BuildResourceManagerURIWithSuffix(sdk.NewSubscriptionID(), "/resourcegroups", client.subscriptionId, client.apiVersion),
+	}
+
+	return sdk.NewPager[GetResourceGroup](client.baseClient, req)
+}
+
+func (client ResourceGroupsClient) Update(ctx context.Context, id ResourceGroupID, input UpdateResourceGroupInput) error {
 	req := sdk.PatchHttpRequestInput{
 		Body: input,
 		ExpectedStatusCodes: []int{
-			http.StatusOK, // already exists
+			http.StatusOK, // ok
 		},
 		Uri: sdk.BuildResourceManagerURI(id, client.subscriptionId, client.apiVersion),
 	}
+
 	if _, err := client.baseClient.PatchJson(ctx, req); err != nil {
-		return fmt.Errorf("sending Request: %+v", err)
+		return fmt.Errorf("sending Request: %w", err)
 	}
 	return nil
 }
 
-func (client Client) MetaData() sdk.ClientMetaData {
+func (client ResourceGroupsClient) MetaData() sdk.ClientMetaData {
 	resourceProvider := "Microsoft.Resources"
 	return sdk.ClientMetaData{
 		ResourceProvider: &resourceProvider,
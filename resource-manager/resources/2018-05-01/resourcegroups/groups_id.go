@@ -1,9 +1,17 @@
 package resourcegroups
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk"
+)
 
 type ResourceGroupID struct {
-	Name string
+	// SubscriptionId is the Subscription this resource is within, which defaults to the
+	// Subscription the client is configured for when empty
+	SubscriptionId string
+	Name           string
 }
 
 func NewResourceGroupID(name string) ResourceGroupID {
@@ -13,5 +21,51 @@ func NewResourceGroupID(name string) ResourceGroupID {
 }
 
 func (id ResourceGroupID) ID(subscriptionId string) string {
+	if id.SubscriptionId != "" {
+		subscriptionId = id.SubscriptionId
+	}
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionId, id.Name)
 }
+
+// Segments returns a slice of Resource ID Segments which comprise this ResourceGroup ID
+func (id ResourceGroupID) Segments() []sdk.Segment {
+	return []sdk.Segment{
+		sdk.StaticSegment("staticSubscriptions", "subscriptions"),
+		sdk.SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		sdk.StaticSegment("staticResourceGroups", "resourceGroups"),
+		sdk.UserSpecifiedSegment("name", "example-resources"),
+	}
+}
+
+// String returns a human-readable description of this ResourceGroup ID
+func (id ResourceGroupID) String() string {
+	components := []string{
+		fmt.Sprintf("Name %q", id.Name),
+	}
+	return fmt.Sprintf("Resource Group (%s)", strings.Join(components, "\n"))
+}
+
+// ParseResourceGroupID parses 'input' into a ResourceGroupID
+func ParseResourceGroupID(input string) (*ResourceGroupID, error) {
+	return parseResourceGroupID(input, false)
+}
+
+// ParseResourceGroupIDInsensitively parses 'input' case-insensitively into a ResourceGroupID
+// note: this method should only be used for API response data and not user input
+func ParseResourceGroupIDInsensitively(input string) (*ResourceGroupID, error) {
+	return parseResourceGroupID(input, true)
+}
+
+func parseResourceGroupID(input string, insensitively bool) (*ResourceGroupID, error) {
+	parser := sdk.NewResourceIDParser(ResourceGroupID{}.Segments())
+	parsed, err := parser.Parse(input, insensitively)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q as a ResourceGroup ID: %+v", input, err)
+	}
+
+	id := ResourceGroupID{
+		SubscriptionId: parsed.Parsed["subscriptionId"],
+		Name:           parsed.Parsed["name"],
+	}
+	return &id, nil
+}
@@ -0,0 +1,45 @@
+package resourcegroups
+
+import "testing"
+
+func TestParseResourceGroupID(t *testing.T) {
+	id := NewResourceGroupID("example")
+	parsed, err := ParseResourceGroupID(id.ID("1234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Name != id.Name || parsed.SubscriptionId != "1234" {
+		t.Fatalf("expected %+v in the subscription %q but got %+v", id, "1234", *parsed)
+	}
+
+	if _, err := ParseResourceGroupID("/subscriptions/1234/resourcegroups/example"); err == nil {
+		t.Fatal("expected an error when parsing a Resource ID with different casing")
+	}
+
+	parsed, err = ParseResourceGroupIDInsensitively("/subscriptions/1234/resourcegroups/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Name != id.Name || parsed.SubscriptionId != "1234" {
+		t.Fatalf("expected %+v in the subscription %q but got %+v", id, "1234", *parsed)
+	}
+}
+
+func TestParseResourceGroupIDInAnotherSubscription(t *testing.T) {
+	parsed, err := ParseResourceGroupID("/subscriptions/other/resourceGroups/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the parsed subscription must be used rather than the subscription the client is configured for
+	expected := "/subscriptions/other/resourceGroups/example"
+	if actual := parsed.ID("1234"); actual != expected {
+		t.Fatalf("expected the ID to be %q but got %q", expected, actual)
+	}
+
+	// whereas an ID which isn't parsed uses the subscription the client is configured for
+	expected = "/subscriptions/1234/resourceGroups/example"
+	if actual := NewResourceGroupID("example").ID("1234"); actual != expected {
+		t.Fatalf("expected the ID to be %q but got %q", expected, actual)
+	}
+}
@@ -23,16 +23,42 @@ func (input CreateResourceGroupInput) Validate() error {
 	return fmt.Errorf("errors: %+v", errors)
 }
 
+type ExportTemplateResourceGroupInput struct {
+	// Options is a comma-separated list of options, such as `IncludeParameterDefaultValue` or `SkipAllParameterization`
+	Options *string `json:"options,omitempty"`
+
+	// Resources are the IDs of the Resources to export, where `*` exports all Resources
+	Resources []string `json:"resources"`
+}
+
+type ExportTemplateResourceGroup struct {
+	Template interface{} `json:"template"`
+}
+
+type ExportTemplateResourceGroupResponse struct {
+	HttpResponse *http.Response
+	Model        *ExportTemplateResourceGroup
+}
+
 type GetResourceGroup struct {
+	ID       string            `json:"id,omitempty"`
 	Location string            `json:"location"`
+	Name     string            `json:"name,omitempty"`
 	Tags     map[string]string `json:"tags"`
 }
 
 type GetResourceGroupResponse struct {
+	// ETag is the current version of the resource, which can be used for conditional requests
+	ETag          *string
 	HttpResponse  *http.Response
 	ResourceGroup *GetResourceGroup
 }
 
+type ListResourceGroupOptions struct {
+	Filter *string
+	Top    *int
+}
+
 type UpdateResourceGroupInput struct {
 	Tags *map[string]string `json:"tags,omitempty"`
 }
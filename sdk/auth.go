@@ -3,18 +3,20 @@ package sdk
 import (
 	"context"
 	"fmt"
-
-	"github.com/Azure/go-autorest/autorest/adal"
-	"github.com/tombuildsstuff/pandora/sdk/endpoints"
+	"time"
 )
 
 type Authorizer interface {
 	Token(ctx context.Context, endpoint string) (*Token, error)
+
+	// AuxiliaryTokens returns a token for the specified endpoint in each of the specified tenants,
+	// which are sent alongside the primary token when making cross-tenant requests
+	AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error)
 }
 
 type Token struct {
-	// todo: expires etc
 	accessToken string
+	expiresOn   time.Time
 	kind        string
 }
 
@@ -22,46 +24,29 @@ func (t Token) AuthorizationHeader() string {
 	return fmt.Sprintf("%s %s", t.kind, t.accessToken)
 }
 
-type ClientSecretAuthorizer struct {
-	activeDirectoryEndpoint string
-	clientId                string
-	clientSecret            string
-	tenantId                string
-}
-
-func NewClientSecretAuthorizer(clientId, clientSecret, tenantId string) Authorizer {
-	return NewClientSecretAuthorizerForEndpoint(clientId, clientSecret, tenantId, endpoints.DefaultActiveDirectoryEndpoint)
+// ExpiresOn returns the time at which this token stops being valid
+func (t Token) ExpiresOn() time.Time {
+	return t.expiresOn
 }
 
-func NewClientSecretAuthorizerForEndpoint(clientId, clientSecret, tenantId, activeDirectoryEndpoint string) Authorizer {
-	return &ClientSecretAuthorizer{
-		activeDirectoryEndpoint: activeDirectoryEndpoint,
-		clientId:     clientId,
-		clientSecret: clientSecret,
-		tenantId:     tenantId,
-	}
+// expiresWithin returns whether this token will have expired within the specified duration
+// tokens without a known expiry are considered to have already expired
+func (t Token) expiresWithin(duration time.Duration) bool {
+	return t.expiresOn.IsZero() || time.Now().Add(duration).After(t.expiresOn)
 }
 
-func (a ClientSecretAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
-	// TODO: obviously make something ourselves here
-	oauth, err := adal.NewOAuthConfig(a.activeDirectoryEndpoint, a.tenantId)
-	if err != nil {
-		return nil, err
-	}
+type tokenForTenantFunc func(ctx context.Context, tenantId, endpoint string) (*Token, error)
 
-	spt, err := adal.NewServicePrincipalToken(*oauth, a.clientId, a.clientSecret, endpoint)
-	if err != nil {
-		return nil, err
-	}
+func auxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string, tokenForTenant tokenForTenantFunc) ([]*Token, error) {
+	tokens := make([]*Token, 0)
+	for _, tenantId := range tenantIds {
+		token, err := tokenForTenant(ctx, tenantId, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining token for auxiliary tenant %q: %+v", tenantId, err)
+		}
 
-	if err = spt.RefreshWithContext(ctx); err != nil {
-		return nil, err
+		tokens = append(tokens, token)
 	}
 
-	token := spt.Token()
-	result := Token{
-		accessToken: token.AccessToken,
-		kind:        "Bearer",
-	}
-	return &result, nil
+	return tokens, nil
 }
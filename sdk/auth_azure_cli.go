@@ -0,0 +1,152 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// AzureCliCommandFunc runs the Azure CLI at the specified path with the specified arguments, returning stdout
+type AzureCliCommandFunc func(ctx context.Context, path string, args ...string) ([]byte, error)
+
+type AzureCliAuthorizerOptions struct {
+	// TenantId is the tenant to obtain tokens for, defaulting to the tenant of the Azure CLI's default subscription
+	TenantId string
+
+	// Path is the path to the Azure CLI, defaulting to `az` from the PATH
+	Path string
+
+	// Command runs the Azure CLI, defaulting to executing Path
+	Command AzureCliCommandFunc
+}
+
+// AzureCliAuthorizer obtains tokens for the account signed into the Azure CLI, intended for local development
+type AzureCliAuthorizer struct {
+	command  AzureCliCommandFunc
+	path     string
+	tenantId string
+
+	tokens *tokenCache
+}
+
+func NewAzureCliAuthorizer(options AzureCliAuthorizerOptions) Authorizer {
+	path := options.Path
+	if path == "" {
+		path = "az"
+	}
+
+	command := options.Command
+	if command == nil {
+		command = runAzureCli
+	}
+
+	return &AzureCliAuthorizer{
+		command:  command,
+		path:     path,
+		tenantId: options.TenantId,
+		tokens:   newTokenCache(),
+	}
+}
+
+// Token returns a cached token for the specified endpoint, refreshing it when it's close to expiring
+func (a *AzureCliAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	return a.tokenForTenant(ctx, a.tenantId, endpoint)
+}
+
+// AuxiliaryTokens returns a cached token for the specified endpoint in each of the specified tenants
+func (a *AzureCliAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	return auxiliaryTokens(ctx, endpoint, tenantIds, a.tokenForTenant)
+}
+
+func (a *AzureCliAuthorizer) tokenForTenant(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	return a.tokens.getOrRefresh(ctx, tokenCacheKey(tenantId, endpoint), func(ctx context.Context) (*Token, error) {
+		return a.refreshToken(ctx, tenantId, endpoint)
+	})
+}
+
+func (a *AzureCliAuthorizer) refreshToken(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	args := []string{"account", "get-access-token", "--resource", endpoint, "--output", "json"}
+	if tenantId != "" {
+		args = append(args, "--tenant", tenantId)
+	}
+
+	output, err := a.command(ctx, a.path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("obtaining token from the Azure CLI: %w", err)
+	}
+
+	var out azureCliTokenResponse
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("unmarshalling Azure CLI token: %+v", err)
+	}
+	if out.AccessToken == "" {
+		return nil, fmt.Errorf("the Azure CLI token did not contain an `accessToken`")
+	}
+
+	expiresOn, err := out.expiresOn()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenType := out.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return &Token{
+		accessToken: out.AccessToken,
+		expiresOn:   *expiresOn,
+		kind:        tokenType,
+	}, nil
+}
+
+type azureCliTokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	TokenType   string `json:"tokenType"`
+
+	// ExpiresOnTimestamp is only returned by more recent versions of the Azure CLI
+	ExpiresOnTimestamp int64 `json:"expires_on"`
+
+	// ExpiresOn is a timestamp in local time, e.g. `2020-01-01 12:30:00.000000`
+	ExpiresOn string `json:"expiresOn"`
+}
+
+func (r azureCliTokenResponse) expiresOn() (*time.Time, error) {
+	if r.ExpiresOnTimestamp != 0 {
+		expiresOn := time.Unix(r.ExpiresOnTimestamp, 0)
+		return &expiresOn, nil
+	}
+
+	expiresOn, err := time.ParseInLocation("2006-01-02 15:04:05.999999", r.ExpiresOn, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("parsing `expiresOn` %q: %+v", r.ExpiresOn, err)
+	}
+
+	return &expiresOn, nil
+}
+
+func runAzureCli(ctx context.Context, path string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, AuthorizerUnavailableError{
+				Err: fmt.Errorf("the Azure CLI wasn't found at %q: %+v", path, err),
+			}
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("running %q: %+v: %s", path, err, message)
+		}
+		return nil, fmt.Errorf("running %q: %+v", path, err)
+	}
+
+	return stdout.Bytes(), nil
+}
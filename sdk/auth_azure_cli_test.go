@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAzureCliAuthorizerToken(t *testing.T) {
+	invocations := 0
+	auth := NewAzureCliAuthorizer(AzureCliAuthorizerOptions{
+		TenantId: "some-tenant",
+		Path:     "/opt/az",
+		Command: func(ctx context.Context, path string, args ...string) ([]byte, error) {
+			invocations++
+			if path != "/opt/az" {
+				t.Fatalf("expected the path to be %q but got %q", "/opt/az", path)
+			}
+
+			expected := "account get-access-token --resource https://management.azure.com --output json --tenant some-tenant"
+			if actual := strings.Join(args, " "); actual != expected {
+				t.Fatalf("expected the arguments to be %q but got %q", expected, actual)
+			}
+
+			expiresOn := time.Now().Add(time.Hour).Format("2006-01-02 15:04:05.000000")
+			return []byte(fmt.Sprintf(`{
+  "accessToken": "abc123",
+  "expiresOn": %q,
+  "subscription": "00000000-0000-0000-0000-000000000000",
+  "tenant": "some-tenant",
+  "tokenType": "Bearer"
+}`, expiresOn)), nil
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		token, err := auth.Token(context.TODO(), "https://management.azure.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := token.AuthorizationHeader(); actual != "Bearer abc123" {
+			t.Fatalf("expected the header to be %q but got %q", "Bearer abc123", actual)
+		}
+	}
+
+	if invocations != 1 {
+		t.Fatalf("expected the Azure CLI to be invoked once but got %d", invocations)
+	}
+}
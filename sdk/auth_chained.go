@@ -0,0 +1,324 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+// AuthorizerSource is a named way of building an Authorizer, used as a link in a ChainedAuthorizer
+type AuthorizerSource struct {
+	Name string
+
+	// Build returns an Authorizer, or an error when this source isn't configured
+	Build func() (Authorizer, error)
+}
+
+// AuthorizerAttempt records why an AuthorizerSource couldn't be used
+type AuthorizerAttempt struct {
+	Source string
+	Error  error
+}
+
+// ChainedAuthorizerError is returned when none of the AuthorizerSources in a chain could be used
+type ChainedAuthorizerError struct {
+	Attempts []AuthorizerAttempt
+}
+
+func (e ChainedAuthorizerError) Error() string {
+	if len(e.Attempts) == 0 {
+		return "no authorizer sources were configured"
+	}
+
+	messages := make([]string, 0)
+	for _, attempt := range e.Attempts {
+		messages = append(messages, fmt.Sprintf("  - %s: %+v", attempt.Source, attempt.Error))
+	}
+
+	return fmt.Sprintf("unable to obtain a token from any of the authorizer sources:\n%s", strings.Join(messages, "\n"))
+}
+
+// AuthorizerUnavailableError is returned by an Authorizer which can't be used in this environment, for example when
+// the Instance Metadata Service isn't reachable or the Azure CLI isn't installed - in which case a ChainedAuthorizer
+// moves on to the next Authorizer, rather than returning the error
+type AuthorizerUnavailableError struct {
+	Err error
+}
+
+func (e AuthorizerUnavailableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e AuthorizerUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// ChainedAuthorizer tries each of it's Authorizers in order, using the first which is able to obtain a token
+type ChainedAuthorizer struct {
+	attempts    []AuthorizerAttempt
+	authorizers []chainedAuthorizerLink
+
+	lock     sync.Mutex
+	selected Authorizer
+}
+
+type chainedAuthorizerLink struct {
+	name       string
+	authorizer Authorizer
+}
+
+// NewChainedAuthorizer builds each of the specified sources, returning a ChainedAuthorizerError when none can be built
+func NewChainedAuthorizer(sources ...AuthorizerSource) (Authorizer, error) {
+	chain := ChainedAuthorizer{
+		attempts:    make([]AuthorizerAttempt, 0),
+		authorizers: make([]chainedAuthorizerLink, 0),
+	}
+
+	for _, source := range sources {
+		authorizer, err := source.Build()
+		if err != nil {
+			chain.attempts = append(chain.attempts, AuthorizerAttempt{
+				Source: source.Name,
+				Error:  err,
+			})
+			continue
+		}
+
+		chain.authorizers = append(chain.authorizers, chainedAuthorizerLink{
+			name:       source.Name,
+			authorizer: authorizer,
+		})
+	}
+
+	if len(chain.authorizers) == 0 {
+		return nil, ChainedAuthorizerError{
+			Attempts: chain.attempts,
+		}
+	}
+
+	return &chain, nil
+}
+
+// NewDefaultAuthorizer returns a ChainedAuthorizer using the DefaultAuthorizerSources
+func NewDefaultAuthorizer() (Authorizer, error) {
+	return NewChainedAuthorizer(DefaultAuthorizerSources()...)
+}
+
+// Token returns a token from the first Authorizer in the chain which is available, which is then used for all future requests.
+// An error from an available Authorizer (for example an invalid Client Secret) is returned rather than trying the next
+// Authorizer, since otherwise requests would silently be made using a different identity
+func (a *ChainedAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	if selected := a.selectedAuthorizer(); selected != nil {
+		return selected.Token(ctx, endpoint)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// another caller may have selected an authorizer whilst we were waiting
+	if a.selected != nil {
+		return a.selected.Token(ctx, endpoint)
+	}
+
+	attempts := append([]AuthorizerAttempt{}, a.attempts...)
+	for _, link := range a.authorizers {
+		token, err := link.authorizer.Token(ctx, endpoint)
+		if err != nil {
+			var unavailable AuthorizerUnavailableError
+			if !errors.As(err, &unavailable) {
+				return nil, fmt.Errorf("obtaining token using %s: %w", link.name, err)
+			}
+
+			attempts = append(attempts, AuthorizerAttempt{
+				Source: link.name,
+				Error:  err,
+			})
+			continue
+		}
+
+		a.selected = link.authorizer
+		return token, nil
+	}
+
+	return nil, ChainedAuthorizerError{
+		Attempts: attempts,
+	}
+}
+
+// AuxiliaryTokens returns tokens for the specified tenants from the Authorizer selected by Token
+func (a *ChainedAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	selected := a.selectedAuthorizer()
+	if selected == nil {
+		// obtaining a token for the primary tenant selects the authorizer to use
+		if _, err := a.Token(ctx, endpoint); err != nil {
+			return nil, err
+		}
+		selected = a.selectedAuthorizer()
+	}
+
+	return selected.AuxiliaryTokens(ctx, endpoint, tenantIds)
+}
+
+func (a *ChainedAuthorizer) selectedAuthorizer() Authorizer {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.selected
+}
+
+// DefaultAuthorizerSources returns the sources used by NewDefaultAuthorizer, which are (in order):
+// a Client Secret, a Client Certificate, an OIDC token, a Managed Identity and then the Azure CLI
+// the Environment can be specified using `ARM_ENVIRONMENT`, defaulting to `environments.Default`
+func DefaultAuthorizerSources() []AuthorizerSource {
+	return []AuthorizerSource{
+		EnvironmentClientSecretAuthorizerSource(),
+		EnvironmentClientCertificateAuthorizerSource(),
+		EnvironmentOIDCAuthorizerSource(),
+		ManagedIdentityAuthorizerSource(),
+		AzureCliAuthorizerSource(),
+	}
+}
+
+// EnvironmentClientSecretAuthorizerSource uses `ARM_CLIENT_ID`, `ARM_CLIENT_SECRET` and `ARM_TENANT_ID`
+func EnvironmentClientSecretAuthorizerSource() AuthorizerSource {
+	return AuthorizerSource{
+		Name: "Client Secret (environment)",
+		Build: func() (Authorizer, error) {
+			values, err := requiredEnvironmentVariables("ARM_CLIENT_ID", "ARM_CLIENT_SECRET", "ARM_TENANT_ID")
+			if err != nil {
+				return nil, err
+			}
+
+			environment, err := environmentFromEnvironmentVariables()
+			if err != nil {
+				return nil, err
+			}
+
+			return NewClientSecretAuthorizerForEndpoint(values[0], values[1], values[2], environment.ActiveDirectoryEndpoint), nil
+		},
+	}
+}
+
+// EnvironmentClientCertificateAuthorizerSource uses `ARM_CLIENT_ID`, `ARM_CLIENT_CERTIFICATE_PATH`, `ARM_TENANT_ID`
+// and optionally `ARM_CLIENT_CERTIFICATE_PASSWORD`
+func EnvironmentClientCertificateAuthorizerSource() AuthorizerSource {
+	return AuthorizerSource{
+		Name: "Client Certificate (environment)",
+		Build: func() (Authorizer, error) {
+			values, err := requiredEnvironmentVariables("ARM_CLIENT_ID", "ARM_CLIENT_CERTIFICATE_PATH", "ARM_TENANT_ID")
+			if err != nil {
+				return nil, err
+			}
+
+			environment, err := environmentFromEnvironmentVariables()
+			if err != nil {
+				return nil, err
+			}
+
+			return NewClientCertificateAuthorizer(ClientCertificateAuthorizerOptions{
+				ActiveDirectoryEndpoint: environment.ActiveDirectoryEndpoint,
+				ClientId:                values[0],
+				CertificatePath:         values[1],
+				CertificatePassword:     os.Getenv("ARM_CLIENT_CERTIFICATE_PASSWORD"),
+				TenantId:                values[2],
+			})
+		},
+	}
+}
+
+// EnvironmentOIDCAuthorizerSource uses `ARM_CLIENT_ID` and `ARM_TENANT_ID` (or `AZURE_CLIENT_ID` and `AZURE_TENANT_ID`)
+// together with the federated token in `ARM_OIDC_TOKEN_FILE_PATH` (or `AZURE_FEDERATED_TOKEN_FILE`)
+func EnvironmentOIDCAuthorizerSource() AuthorizerSource {
+	return AuthorizerSource{
+		Name: "OIDC (environment)",
+		Build: func() (Authorizer, error) {
+			clientId := firstEnvironmentVariable("ARM_CLIENT_ID", "AZURE_CLIENT_ID")
+			tenantId := firstEnvironmentVariable("ARM_TENANT_ID", "AZURE_TENANT_ID")
+			tokenFilePath := firstEnvironmentVariable("ARM_OIDC_TOKEN_FILE_PATH", "AZURE_FEDERATED_TOKEN_FILE")
+			if clientId == "" || tenantId == "" || tokenFilePath == "" {
+				return nil, fmt.Errorf("a Client ID, Tenant ID and Token File Path must be set in the environment")
+			}
+
+			environment, err := environmentFromEnvironmentVariables()
+			if err != nil {
+				return nil, err
+			}
+
+			return NewOIDCAuthorizer(OIDCAuthorizerOptions{
+				ActiveDirectoryEndpoint: environment.ActiveDirectoryEndpoint,
+				ClientId:                clientId,
+				TenantId:                tenantId,
+				TokenFilePath:           tokenFilePath,
+			})
+		},
+	}
+}
+
+// ManagedIdentityAuthorizerSource uses the System Assigned Identity, or the User Assigned Identity
+// specified by `ARM_MSI_CLIENT_ID` when set - which is separate from `ARM_CLIENT_ID`, since that's the
+// Service Principal used by the other sources
+func ManagedIdentityAuthorizerSource() AuthorizerSource {
+	return AuthorizerSource{
+		Name: "Managed Identity",
+		Build: func() (Authorizer, error) {
+			return NewManagedIdentityAuthorizer(ManagedIdentityAuthorizerOptions{
+				ClientId: os.Getenv("ARM_MSI_CLIENT_ID"),
+			})
+		},
+	}
+}
+
+// AzureCliAuthorizerSource uses the account signed into the Azure CLI, optionally for the tenant in `ARM_TENANT_ID`
+func AzureCliAuthorizerSource() AuthorizerSource {
+	return AuthorizerSource{
+		Name: "Azure CLI",
+		Build: func() (Authorizer, error) {
+			return NewAzureCliAuthorizer(AzureCliAuthorizerOptions{
+				TenantId: os.Getenv("ARM_TENANT_ID"),
+			}), nil
+		},
+	}
+}
+
+func environmentFromEnvironmentVariables() (*environments.Environment, error) {
+	name := os.Getenv("ARM_ENVIRONMENT")
+	if name == "" {
+		environment := environments.Default
+		return &environment, nil
+	}
+
+	return environments.FromName(name)
+}
+
+func requiredEnvironmentVariables(names ...string) ([]string, error) {
+	values := make([]string, 0)
+	missing := make([]string, 0)
+	for _, name := range names {
+		value := os.Getenv(name)
+		if value == "" {
+			missing = append(missing, name)
+		}
+		values = append(values, value)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("the environment variables %s must be set", strings.Join(missing, ", "))
+	}
+
+	return values, nil
+}
+
+func firstEnvironmentVariable(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,198 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeAuthorizer struct {
+	endpoints []string
+	err       error
+	tokens    int
+}
+
+func (a *fakeAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	a.endpoints = append(a.endpoints, endpoint)
+	a.tokens++
+	return &Token{
+		accessToken: "abc123",
+		expiresOn:   time.Now().Add(time.Hour),
+		kind:        "Bearer",
+	}, nil
+}
+
+func (a *fakeAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	tokens := make([]*Token, 0)
+	for _, tenantId := range tenantIds {
+		if a.err != nil {
+			return nil, a.err
+		}
+
+		tokens = append(tokens, &Token{
+			accessToken: tenantId,
+			expiresOn:   time.Now().Add(time.Hour),
+			kind:        "Bearer",
+		})
+	}
+	return tokens, nil
+}
+
+func TestChainedAuthorizerUsesFirstWorkingAuthorizer(t *testing.T) {
+	failing := &fakeAuthorizer{err: AuthorizerUnavailableError{Err: fmt.Errorf("not installed")}}
+	working := &fakeAuthorizer{}
+	unused := &fakeAuthorizer{}
+
+	auth, err := NewChainedAuthorizer(
+		AuthorizerSource{
+			Name: "Unconfigured",
+			Build: func() (Authorizer, error) {
+				return nil, fmt.Errorf("not configured")
+			},
+		},
+		AuthorizerSource{
+			Name: "Failing",
+			Build: func() (Authorizer, error) {
+				return failing, nil
+			},
+		},
+		AuthorizerSource{
+			Name: "Working",
+			Build: func() (Authorizer, error) {
+				return working, nil
+			},
+		},
+		AuthorizerSource{
+			Name: "Unused",
+			Build: func() (Authorizer, error) {
+				return unused, nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := auth.Token(context.TODO(), "https://management.azure.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if working.tokens != 2 {
+		t.Fatalf("expected the working authorizer to be used twice but got %d", working.tokens)
+	}
+	if unused.tokens != 0 {
+		t.Fatalf("expected the unused authorizer not to be used but got %d", unused.tokens)
+	}
+}
+
+func TestChainedAuthorizerAggregatesErrors(t *testing.T) {
+	auth, err := NewChainedAuthorizer(
+		AuthorizerSource{
+			Name: "Unconfigured",
+			Build: func() (Authorizer, error) {
+				return nil, fmt.Errorf("not configured")
+			},
+		},
+		AuthorizerSource{
+			Name: "Failing",
+			Build: func() (Authorizer, error) {
+				return &fakeAuthorizer{err: AuthorizerUnavailableError{Err: fmt.Errorf("not installed")}}, nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = auth.Token(context.TODO(), "https://management.azure.com")
+	var chainErr ChainedAuthorizerError
+	if !errors.As(err, &chainErr) {
+		t.Fatalf("expected a ChainedAuthorizerError but got %+v", err)
+	}
+	if len(chainErr.Attempts) != 2 {
+		t.Fatalf("expected 2 attempts but got %d", len(chainErr.Attempts))
+	}
+	for _, expected := range []string{"Unconfigured: not configured", "Failing: not installed"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected the error to contain %q but got %q", expected, err.Error())
+		}
+	}
+}
+
+func TestChainedAuthorizerReturnsErrorsFromAvailableAuthorizers(t *testing.T) {
+	cliCalls := 0
+	auth, err := NewChainedAuthorizer(
+		AuthorizerSource{
+			Name: "Client Secret",
+			Build: func() (Authorizer, error) {
+				return &fakeAuthorizer{err: fmt.Errorf("invalid client secret")}, nil
+			},
+		},
+		AuthorizerSource{
+			Name: "Azure CLI",
+			Build: func() (Authorizer, error) {
+				return NewAzureCliAuthorizer(AzureCliAuthorizerOptions{
+					Command: func(ctx context.Context, path string, args ...string) ([]byte, error) {
+						cliCalls++
+						return []byte(`{"accessToken": "abc123", "expires_on": 4102444800}`), nil
+					},
+				}), nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = auth.Token(context.TODO(), "https://management.azure.com")
+	if err == nil || !strings.Contains(err.Error(), "invalid client secret") {
+		t.Fatalf("expected the error from the Client Secret authorizer but got %+v", err)
+	}
+	if cliCalls != 0 {
+		t.Fatalf("expected the Azure CLI not to be used but it was called %d times", cliCalls)
+	}
+}
+
+func TestAzureCliAuthorizerUnavailableWhenNotInstalled(t *testing.T) {
+	auth := NewAzureCliAuthorizer(AzureCliAuthorizerOptions{
+		Path: "pandora-azure-cli-which-does-not-exist",
+	})
+
+	_, err := auth.Token(context.TODO(), "https://management.azure.com")
+	var unavailable AuthorizerUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected an AuthorizerUnavailableError but got %+v", err)
+	}
+}
+
+func TestManagedIdentityAuthorizerSourceClientId(t *testing.T) {
+	// the Service Principal's Client ID mustn't be used as the User Assigned Identity
+	t.Setenv("ARM_CLIENT_ID", "service-principal")
+	t.Setenv("ARM_MSI_CLIENT_ID", "")
+
+	auth, err := ManagedIdentityAuthorizerSource().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := auth.(*ManagedIdentityAuthorizer).clientId; actual != "" {
+		t.Fatalf("expected the System Assigned Identity to be used but got the Client ID %q", actual)
+	}
+
+	t.Setenv("ARM_MSI_CLIENT_ID", "user-assigned-identity")
+	auth, err = ManagedIdentityAuthorizerSource().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := auth.(*ManagedIdentityAuthorizer).clientId; actual != "user-assigned-identity" {
+		t.Fatalf("expected the Client ID to be %q but got %q", "user-assigned-identity", actual)
+	}
+}
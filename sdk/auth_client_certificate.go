@@ -0,0 +1,307 @@
+package sdk
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// clientAssertionLifetime is how long a signed client assertion remains valid for
+const clientAssertionLifetime = 10 * time.Minute
+
+type ClientCertificateAuthorizerOptions struct {
+	// ActiveDirectoryEndpoint is the Azure Active Directory endpoint to obtain tokens from
+	// this defaults to `environments.Default` when unspecified
+	ActiveDirectoryEndpoint string
+
+	ClientId string
+	TenantId string
+
+	// CertificateData is the contents of a PEM or PFX (PKCS#12) file containing the
+	// certificate and private key, alternatively CertificatePath can be specified
+	CertificateData []byte
+
+	// CertificatePath is the path to a PEM or PFX (PKCS#12) file containing the
+	// certificate and private key, used when CertificateData isn't specified
+	CertificatePath string
+
+	// CertificatePassword is the password used to decrypt an encrypted PFX file
+	CertificatePassword string
+
+	// SendCertificateChain specifies whether the certificate chain should be sent in the `x5c`
+	// header of the client assertion, which is required for Subject Name/Issuer authentication
+	SendCertificateChain bool
+}
+
+type ClientCertificateAuthorizer struct {
+	activeDirectoryEndpoint string
+	certificateChain        []*x509.Certificate
+	clientId                string
+	httpClient              *http.Client
+	privateKey              *rsa.PrivateKey
+	sendCertificateChain    bool
+	tenantId                string
+
+	tokens *tokenCache
+}
+
+func NewClientCertificateAuthorizer(options ClientCertificateAuthorizerOptions) (Authorizer, error) {
+	if options.ClientId == "" {
+		return nil, fmt.Errorf("`ClientId` must be specified")
+	}
+	if options.TenantId == "" {
+		return nil, fmt.Errorf("`TenantId` must be specified")
+	}
+
+	data := options.CertificateData
+	if len(data) == 0 {
+		if options.CertificatePath == "" {
+			return nil, fmt.Errorf("either `CertificateData` or `CertificatePath` must be specified")
+		}
+
+		var err error
+		data, err = ioutil.ReadFile(options.CertificatePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading certificate from %q: %+v", options.CertificatePath, err)
+		}
+	}
+
+	privateKey, chain, err := parseClientCertificate(data, options.CertificatePassword)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %+v", err)
+	}
+
+	activeDirectoryEndpoint := options.ActiveDirectoryEndpoint
+	if activeDirectoryEndpoint == "" {
+		activeDirectoryEndpoint = environments.Default.ActiveDirectoryEndpoint
+	}
+
+	return &ClientCertificateAuthorizer{
+		activeDirectoryEndpoint: activeDirectoryEndpoint,
+		certificateChain:        chain,
+		clientId:                options.ClientId,
+		httpClient:              &http.Client{},
+		privateKey:              privateKey,
+		sendCertificateChain:    options.SendCertificateChain,
+		tenantId:                options.TenantId,
+		tokens:                  newTokenCache(),
+	}, nil
+}
+
+// Token returns a cached token for the specified endpoint, refreshing it when it's close to expiring
+func (a *ClientCertificateAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	return a.tokenForTenant(ctx, a.tenantId, endpoint)
+}
+
+// AuxiliaryTokens returns a cached token for the specified endpoint in each of the specified tenants
+func (a *ClientCertificateAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	return auxiliaryTokens(ctx, endpoint, tenantIds, a.tokenForTenant)
+}
+
+func (a *ClientCertificateAuthorizer) tokenForTenant(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	return a.tokens.getOrRefresh(ctx, tokenCacheKey(tenantId, endpoint), func(ctx context.Context) (*Token, error) {
+		return a.refreshToken(ctx, tenantId, endpoint)
+	})
+}
+
+func (a *ClientCertificateAuthorizer) refreshToken(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	assertion, err := a.clientAssertion(tokenEndpoint(a.activeDirectoryEndpoint, tenantId))
+	if err != nil {
+		return nil, fmt.Errorf("building client assertion: %+v", err)
+	}
+
+	request := clientCredentialsTokenRequest{
+		activeDirectoryEndpoint: a.activeDirectoryEndpoint,
+		clientId:                a.clientId,
+		credential: url.Values{
+			"client_assertion":      []string{*assertion},
+			"client_assertion_type": []string{clientAssertionTypeJwtBearer},
+		},
+		scope:    scopeForEndpoint(endpoint),
+		tenantId: tenantId,
+	}
+	return request.execute(ctx, a.httpClient)
+}
+
+// clientAssertion returns a JWT signed by the certificate's private key, for use as a client assertion
+func (a *ClientCertificateAuthorizer) clientAssertion(audience string) (*string, error) {
+	leaf := a.certificateChain[0]
+	thumbprint := sha1.Sum(leaf.Raw)
+	header := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	}
+	if a.sendCertificateChain {
+		chain := make([]string, 0)
+		for _, cert := range a.certificateChain {
+			chain = append(chain, base64.StdEncoding.EncodeToString(cert.Raw))
+		}
+		header["x5c"] = chain
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return nil, fmt.Errorf("generating jti: %+v", err)
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"aud": audience,
+		"exp": now.Add(clientAssertionLifetime).Unix(),
+		"iat": now.Unix(),
+		"iss": a.clientId,
+		"jti": hex.EncodeToString(jti),
+		"nbf": now.Unix(),
+		"sub": a.clientId,
+	}
+
+	encodedHeader, err := encodeJwtSegment(header)
+	if err != nil {
+		return nil, fmt.Errorf("encoding header: %+v", err)
+	}
+	encodedClaims, err := encodeJwtSegment(claims)
+	if err != nil {
+		return nil, fmt.Errorf("encoding claims: %+v", err)
+	}
+
+	unsigned := fmt.Sprintf("%s.%s", encodedHeader, encodedClaims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("signing: %+v", err)
+	}
+
+	assertion := fmt.Sprintf("%s.%s", unsigned, base64.RawURLEncoding.EncodeToString(signature))
+	return &assertion, nil
+}
+
+func encodeJwtSegment(input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// parseClientCertificate parses the RSA private key and certificate chain from either a PEM or PFX file
+// the certificate matching the private key is returned first in the chain
+func parseClientCertificate(data []byte, password string) (*rsa.PrivateKey, []*x509.Certificate, error) {
+	blocks := make([]*pem.Block, 0)
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	// if this isn't a PEM file then it's a PFX file
+	if len(blocks) == 0 {
+		return parsePfxCertificate(data, password)
+	}
+
+	var privateKey *rsa.PrivateKey
+	certificates := make([]*x509.Certificate, 0)
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing certificate: %+v", err)
+			}
+			certificates = append(certificates, cert)
+
+		case "PRIVATE KEY", "RSA PRIVATE KEY":
+			if privateKey != nil {
+				return nil, nil, fmt.Errorf("expected a single private key but found multiple")
+			}
+			if _, encrypted := block.Headers["DEK-Info"]; encrypted {
+				return nil, nil, fmt.Errorf("encrypted PEM private keys are not supported, use an encrypted PFX file instead")
+			}
+
+			key, err := parseRsaPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			privateKey = key
+		}
+	}
+
+	if privateKey == nil {
+		return nil, nil, fmt.Errorf("no private key was found")
+	}
+
+	return orderCertificateChain(privateKey, certificates)
+}
+
+// parsePfxCertificate parses the RSA private key and certificate chain from a PFX file, which can be encrypted
+// using either the legacy (3DES/RC2) or modern (AES/PBES2) algorithms
+func parsePfxCertificate(data []byte, password string) (*rsa.PrivateKey, []*x509.Certificate, error) {
+	key, certificate, caCertificates, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding PFX: %+v", err)
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("only RSA private keys are supported but got %T", key)
+	}
+
+	return orderCertificateChain(privateKey, append([]*x509.Certificate{certificate}, caCertificates...))
+}
+
+// orderCertificateChain returns the certificate chain with the certificate matching the private key first,
+// since it's the one we're authenticating with
+func orderCertificateChain(privateKey *rsa.PrivateKey, certificates []*x509.Certificate) (*rsa.PrivateKey, []*x509.Certificate, error) {
+	var leaf *x509.Certificate
+	chain := make([]*x509.Certificate, 0)
+	for _, cert := range certificates {
+		if publicKey, ok := cert.PublicKey.(*rsa.PublicKey); ok && leaf == nil && publicKey.Equal(&privateKey.PublicKey) {
+			leaf = cert
+			continue
+		}
+		chain = append(chain, cert)
+	}
+	if leaf == nil {
+		return nil, nil, fmt.Errorf("no certificate matching the private key was found")
+	}
+
+	return privateKey, append([]*x509.Certificate{leaf}, chain...), nil
+}
+
+func parseRsaPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %+v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("only RSA private keys are supported but got %T", key)
+	}
+	return rsaKey, nil
+}
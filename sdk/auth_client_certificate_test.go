@@ -0,0 +1,168 @@
+package sdk
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestClientCertificateAuthorizerToken(t *testing.T) {
+	privateKey, certificate := generateTestCertificate(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if actual := r.PostForm.Get("client_assertion_type"); actual != clientAssertionTypeJwtBearer {
+			t.Errorf("expected the `client_assertion_type` to be %q but got %q", clientAssertionTypeJwtBearer, actual)
+		}
+
+		segments := strings.Split(r.PostForm.Get("client_assertion"), ".")
+		if len(segments) != 3 {
+			t.Errorf("expected the client assertion to have 3 segments but got %d", len(segments))
+			return
+		}
+
+		signature, _ := base64.RawURLEncoding.DecodeString(segments[2])
+		hash := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+		if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+			t.Errorf("verifying signature: %+v", err)
+		}
+
+		var header map[string]interface{}
+		headerJson, _ := base64.RawURLEncoding.DecodeString(segments[0])
+		if err := json.Unmarshal(headerJson, &header); err != nil {
+			t.Error(err)
+		}
+		if _, ok := header["x5c"]; !ok {
+			t.Errorf("expected the header to contain `x5c` but got %+v", header)
+		}
+
+		var claims map[string]interface{}
+		claimsJson, _ := base64.RawURLEncoding.DecodeString(segments[1])
+		if err := json.Unmarshal(claimsJson, &claims); err != nil {
+			t.Error(err)
+		}
+		if claims["aud"] != "http://"+r.Host+"/some-tenant/oauth2/v2.0/token" {
+			t.Errorf("unexpected audience %q", claims["aud"])
+		}
+		if claims["sub"] != "some-client" || claims["iss"] != "some-client" {
+			t.Errorf("expected the issuer and subject to be the client id but got %+v", claims)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"abc123"}`))
+	}))
+	defer server.Close()
+
+	keyBytes := x509.MarshalPKCS1PrivateKey(privateKey)
+	data := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: keyBytes})...,
+	)
+	auth, err := NewClientCertificateAuthorizer(ClientCertificateAuthorizerOptions{
+		ActiveDirectoryEndpoint: server.URL,
+		ClientId:                "some-client",
+		TenantId:                "some-tenant",
+		CertificateData:         data,
+		SendCertificateChain:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.Token(context.TODO(), "https://management.azure.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := token.AuthorizationHeader(); actual != "Bearer abc123" {
+		t.Fatalf("expected the header to be %q but got %q", "Bearer abc123", actual)
+	}
+}
+
+func TestClientCertificateAuthorizerMismatchedKey(t *testing.T) {
+	_, certificate := generateTestCertificate(t)
+	otherKey, _ := generateTestCertificate(t)
+
+	data := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(otherKey)})...,
+	)
+	_, err := NewClientCertificateAuthorizer(ClientCertificateAuthorizerOptions{
+		ClientId:        "some-client",
+		TenantId:        "some-tenant",
+		CertificateData: data,
+	})
+	if err == nil {
+		t.Fatal("expected an error but didn't get one")
+	}
+}
+
+func generateTestCertificate(t *testing.T) (*rsa.PrivateKey, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName: "pandora",
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, certificate
+}
+
+func TestClientCertificateAuthorizerPfx(t *testing.T) {
+	privateKey, data := generateTestCertificate(t)
+	certificate, err := x509.ParseCertificate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both legacy (3DES/RC2) and modern (AES-256/PBES2, the default for OpenSSL 3 and Windows) PFX files are supported
+	encoders := map[string]*pkcs12.Encoder{
+		"legacy rc2": pkcs12.LegacyRC2,
+		"legacy des": pkcs12.LegacyDES,
+		"modern":     pkcs12.Modern2023,
+	}
+	for name, encoder := range encoders {
+		pfx, err := encoder.Encode(privateKey, certificate, nil, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, chain, err := parseClientCertificate(pfx, "secret")
+		if err != nil {
+			t.Fatalf("parsing the %s PFX: %+v", name, err)
+		}
+		if !key.Equal(privateKey) || len(chain) != 1 || !chain[0].Equal(certificate) {
+			t.Fatalf("expected the %s PFX to contain the private key and certificate", name)
+		}
+
+		if _, _, err := parseClientCertificate(pfx, "incorrect"); err == nil {
+			t.Fatalf("expected an error for the %s PFX with an incorrect password but didn't get one", name)
+		}
+	}
+}
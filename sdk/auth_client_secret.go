@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+type ClientSecretAuthorizer struct {
+	activeDirectoryEndpoint string
+	clientId                string
+	clientSecret            string
+	httpClient              *http.Client
+	tenantId                string
+
+	tokens *tokenCache
+}
+
+func NewClientSecretAuthorizer(clientId, clientSecret, tenantId string) Authorizer {
+	return NewClientSecretAuthorizerForEndpoint(clientId, clientSecret, tenantId, environments.Default.ActiveDirectoryEndpoint)
+}
+
+func NewClientSecretAuthorizerForEndpoint(clientId, clientSecret, tenantId, activeDirectoryEndpoint string) Authorizer {
+	return &ClientSecretAuthorizer{
+		activeDirectoryEndpoint: activeDirectoryEndpoint,
+		clientId:                clientId,
+		clientSecret:            clientSecret,
+		httpClient:              &http.Client{},
+		tenantId:                tenantId,
+		tokens:                  newTokenCache(),
+	}
+}
+
+// Token returns a cached token for the specified endpoint, refreshing it when it's close to expiring
+func (a *ClientSecretAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	return a.tokenForTenant(ctx, a.tenantId, endpoint)
+}
+
+// AuxiliaryTokens returns a cached token for the specified endpoint in each of the specified tenants
+func (a *ClientSecretAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	return auxiliaryTokens(ctx, endpoint, tenantIds, a.tokenForTenant)
+}
+
+func (a *ClientSecretAuthorizer) tokenForTenant(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	return a.tokens.getOrRefresh(ctx, tokenCacheKey(tenantId, endpoint), func(ctx context.Context) (*Token, error) {
+		return a.refreshToken(ctx, tenantId, endpoint)
+	})
+}
+
+func (a *ClientSecretAuthorizer) refreshToken(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	request := clientCredentialsTokenRequest{
+		activeDirectoryEndpoint: a.activeDirectoryEndpoint,
+		clientId:                a.clientId,
+		credential: url.Values{
+			"client_secret": []string{a.clientSecret},
+		},
+		scope:    scopeForEndpoint(endpoint),
+		tenantId: tenantId,
+	}
+	return request.execute(ctx, a.httpClient)
+}
@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientSecretAuthorizerToken(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/some-tenant/oauth2/v2.0/token" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		expected := map[string]string{
+			"client_id":     "some-client",
+			"client_secret": "some-secret",
+			"grant_type":    "client_credentials",
+			"scope":         "https://management.azure.com/.default",
+		}
+		for k, v := range expected {
+			if actual := r.PostForm.Get(k); actual != v {
+				t.Errorf("expected %q to be %q but got %q", k, v, actual)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"ext_expires_in":3599,"access_token":"abc123"}`))
+	}))
+	defer server.Close()
+
+	auth := NewClientSecretAuthorizerForEndpoint("some-client", "some-secret", "some-tenant", server.URL)
+	for i := 0; i < 2; i++ {
+		token, err := auth.Token(context.TODO(), "https://management.azure.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := token.AuthorizationHeader(); actual != "Bearer abc123" {
+			t.Fatalf("expected the header to be %q but got %q", "Bearer abc123", actual)
+		}
+		if token.ExpiresOn().Before(time.Now().Add(59 * time.Minute)) {
+			t.Fatalf("expected the token to expire in an hour but got %s", token.ExpiresOn())
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected 1 token request but got %d", requests)
+	}
+}
+
+func TestClientSecretAuthorizerActiveDirectoryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{
+  "error": "invalid_client",
+  "error_description": "AADSTS7000215: Invalid client secret provided.",
+  "error_codes": [7000215],
+  "trace_id": "some-trace",
+  "correlation_id": "some-correlation"
+}`))
+	}))
+	defer server.Close()
+
+	auth := NewClientSecretAuthorizerForEndpoint("some-client", "wrong-secret", "some-tenant", server.URL)
+	_, err := auth.Token(context.TODO(), "https://management.azure.com")
+	if err == nil {
+		t.Fatal("expected an error but didn't get one")
+	}
+
+	var aadErr ActiveDirectoryError
+	if !errors.As(err, &aadErr) {
+		t.Fatalf("expected an ActiveDirectoryError but got %+v", err)
+	}
+	if aadErr.Code != "invalid_client" {
+		t.Fatalf("expected the code to be %q but got %q", "invalid_client", aadErr.Code)
+	}
+	if !aadErr.HasErrorCode(InvalidClientSecret) {
+		t.Fatalf("expected the error to contain %s", InvalidClientSecret)
+	}
+	if aadErr.CorrelationId != "some-correlation" {
+		t.Fatalf("expected the correlation id to be %q but got %q", "some-correlation", aadErr.CorrelationId)
+	}
+}
@@ -0,0 +1,241 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultInstanceMetadataEndpoint is the Azure Instance Metadata Service endpoint used to obtain tokens
+	defaultInstanceMetadataEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	instanceMetadataApiVersion = "2018-02-01"
+	appServiceApiVersion       = "2019-08-01"
+)
+
+type ManagedIdentityAuthorizerOptions struct {
+	// ClientId is the Client ID of the User Assigned Identity to authenticate as
+	ClientId string
+
+	// ObjectId is the Object (Principal) ID of the User Assigned Identity to authenticate as
+	ObjectId string
+
+	// ResourceId is the Resource ID of the User Assigned Identity to authenticate as
+	ResourceId string
+
+	// Endpoint overrides the endpoint used to obtain tokens - when unspecified the App Service/Functions
+	// `IDENTITY_ENDPOINT` is used when set, falling back to the Azure Instance Metadata Service
+	Endpoint string
+
+	// IdentityHeader is the secret sent to the App Service/Functions identity endpoint, defaulting to `IDENTITY_HEADER`
+	// when IdentityHeader is set tokens are requested using the App Service protocol
+	IdentityHeader string
+}
+
+// ManagedIdentityAuthorizer obtains tokens for a System or User Assigned Identity from either the
+// Azure Instance Metadata Service or the App Service/Functions identity endpoint
+type ManagedIdentityAuthorizer struct {
+	clientId       string
+	endpoint       string
+	httpClient     *http.Client
+	identityHeader string
+	objectId       string
+	resourceId     string
+
+	tokens *tokenCache
+}
+
+func NewManagedIdentityAuthorizer(options ManagedIdentityAuthorizerOptions) (Authorizer, error) {
+	specified := 0
+	for _, v := range []string{options.ClientId, options.ObjectId, options.ResourceId} {
+		if v != "" {
+			specified++
+		}
+	}
+	if specified > 1 {
+		return nil, fmt.Errorf("only one of `ClientId`, `ObjectId` and `ResourceId` can be specified")
+	}
+
+	endpoint := options.Endpoint
+	identityHeader := options.IdentityHeader
+	if endpoint == "" {
+		endpoint = defaultInstanceMetadataEndpoint
+		if v := os.Getenv("IDENTITY_ENDPOINT"); v != "" && os.Getenv("IDENTITY_HEADER") != "" {
+			endpoint = v
+			if identityHeader == "" {
+				identityHeader = os.Getenv("IDENTITY_HEADER")
+			}
+		}
+	}
+
+	return &ManagedIdentityAuthorizer{
+		clientId: options.ClientId,
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				// the metadata endpoint is link-local, so being unable to connect quickly means it's unavailable
+				DialContext: (&net.Dialer{
+					Timeout: 2 * time.Second,
+				}).DialContext,
+			},
+		},
+		identityHeader: identityHeader,
+		objectId:       options.ObjectId,
+		resourceId:     options.ResourceId,
+		tokens:         newTokenCache(),
+	}, nil
+}
+
+// Token returns a cached token for the specified endpoint, refreshing it when it's close to expiring
+func (a *ManagedIdentityAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	return a.tokens.getOrRefresh(ctx, endpoint, func(ctx context.Context) (*Token, error) {
+		return a.refreshToken(ctx, endpoint)
+	})
+}
+
+// AuxiliaryTokens isn't supported for Managed Identities, since these only exist within a single tenant
+func (a *ManagedIdentityAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	return nil, fmt.Errorf("auxiliary tenants are not supported when authenticating using a Managed Identity")
+}
+
+func (a *ManagedIdentityAuthorizer) refreshToken(ctx context.Context, endpoint string) (*Token, error) {
+	req, err := a.buildRequest(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("building token request: %+v", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("requesting token from %q: %+v", a.endpoint, err)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		// being unable to connect to the endpoint means there's no Managed Identity available
+		return nil, AuthorizerUnavailableError{
+			Err: err,
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading token response: %+v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d obtaining token from the managed identity endpoint: %s", resp.StatusCode, string(body))
+	}
+
+	var out managedIdentityTokenResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("unmarshalling token response: %+v", err)
+	}
+	if out.AccessToken == "" {
+		return nil, fmt.Errorf("token response did not contain an `access_token`")
+	}
+
+	expiresOn, err := out.expiresOn()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenType := out.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return &Token{
+		accessToken: out.AccessToken,
+		expiresOn:   *expiresOn,
+		kind:        tokenType,
+	}, nil
+}
+
+func (a *ManagedIdentityAuthorizer) buildRequest(ctx context.Context, resource string) (*http.Request, error) {
+	uri, err := url.Parse(a.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing endpoint %q: %+v", a.endpoint, err)
+	}
+
+	query := uri.Query()
+	query.Set("resource", resource)
+
+	headers := http.Header{}
+	if a.identityHeader != "" {
+		// App Service & Functions
+		query.Set("api-version", appServiceApiVersion)
+		headers.Set("X-IDENTITY-HEADER", a.identityHeader)
+		if a.clientId != "" {
+			query.Set("client_id", a.clientId)
+		}
+		if a.objectId != "" {
+			query.Set("principal_id", a.objectId)
+		}
+		if a.resourceId != "" {
+			query.Set("mi_res_id", a.resourceId)
+		}
+	} else {
+		// Instance Metadata Service
+		query.Set("api-version", instanceMetadataApiVersion)
+		headers.Set("Metadata", "true")
+		if a.clientId != "" {
+			query.Set("client_id", a.clientId)
+		}
+		if a.objectId != "" {
+			query.Set("object_id", a.objectId)
+		}
+		if a.resourceId != "" {
+			query.Set("msi_res_id", a.resourceId)
+		}
+	}
+	uri.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = headers
+	return req, nil
+}
+
+type managedIdentityTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+
+	// these are returned as either strings or numbers depending on the endpoint
+	ExpiresIn json.RawMessage `json:"expires_in"`
+	ExpiresOn json.RawMessage `json:"expires_on"`
+}
+
+func (r managedIdentityTokenResponse) expiresOn() (*time.Time, error) {
+	if v := strings.Trim(string(r.ExpiresOn), `"`); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			expiresOn := time.Unix(seconds, 0)
+			return &expiresOn, nil
+		}
+
+		// older versions of the App Service endpoint return a date, e.g. `06/20/2019 02:57:58 +00:00`
+		if expiresOn, err := time.Parse("01/02/2006 15:04:05 -07:00", v); err == nil {
+			return &expiresOn, nil
+		}
+	}
+
+	if v := strings.Trim(string(r.ExpiresIn), `"`); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			expiresOn := time.Now().Add(time.Duration(seconds) * time.Second)
+			return &expiresOn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("parsing token expiry: `expires_on` was %q and `expires_in` was %q", string(r.ExpiresOn), string(r.ExpiresIn))
+}
@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestManagedIdentityAuthorizerInstanceMetadata(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			t.Errorf("expected the `Metadata` header to be set")
+		}
+		query := r.URL.Query()
+		expected := map[string]string{
+			"api-version": instanceMetadataApiVersion,
+			"client_id":   "some-client",
+			"resource":    "https://management.azure.com",
+		}
+		for k, v := range expected {
+			if actual := query.Get(k); actual != v {
+				t.Errorf("expected %q to be %q but got %q", k, v, actual)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fmt.Sprintf(`{"access_token":"abc123","expires_in":"3599","expires_on":"%d","resource":"https://management.azure.com","token_type":"Bearer"}`, expiresOn)))
+	}))
+	defer server.Close()
+
+	auth, err := NewManagedIdentityAuthorizer(ManagedIdentityAuthorizerOptions{
+		ClientId: "some-client",
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.Token(context.TODO(), "https://management.azure.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := token.AuthorizationHeader(); actual != "Bearer abc123" {
+		t.Fatalf("expected the header to be %q but got %q", "Bearer abc123", actual)
+	}
+	if token.ExpiresOn().Unix() != expiresOn {
+		t.Fatalf("expected the token to expire at %d but got %d", expiresOn, token.ExpiresOn().Unix())
+	}
+}
+
+func TestManagedIdentityAuthorizerAppService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-IDENTITY-HEADER") != "some-secret" {
+			t.Errorf("expected the `X-IDENTITY-HEADER` header to be set")
+		}
+		query := r.URL.Query()
+		if actual := query.Get("api-version"); actual != appServiceApiVersion {
+			t.Errorf("expected the api version to be %q but got %q", appServiceApiVersion, actual)
+		}
+		if actual := query.Get("mi_res_id"); actual != "/subscriptions/123/resourceGroups/group1/providers/Microsoft.ManagedIdentity/userAssignedIdentities/identity1" {
+			t.Errorf("unexpected `mi_res_id` %q", actual)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"abc123","expires_on":1700000000,"resource":"https://management.azure.com","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	auth, err := NewManagedIdentityAuthorizer(ManagedIdentityAuthorizerOptions{
+		Endpoint:       server.URL,
+		IdentityHeader: "some-secret",
+		ResourceId:     "/subscriptions/123/resourceGroups/group1/providers/Microsoft.ManagedIdentity/userAssignedIdentities/identity1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.Token(context.TODO(), "https://management.azure.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ExpiresOn().Unix() != 1700000000 {
+		t.Fatalf("expected the token to expire at 1700000000 but got %d", token.ExpiresOn().Unix())
+	}
+}
+
+func TestManagedIdentityAuthorizerUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	server.Close()
+
+	auth, err := NewManagedIdentityAuthorizer(ManagedIdentityAuthorizerOptions{
+		Endpoint: endpoint,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = auth.Token(context.TODO(), "https://management.azure.com")
+	var unavailable AuthorizerUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected an AuthorizerUnavailableError but got %+v", err)
+	}
+}
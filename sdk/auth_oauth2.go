@@ -0,0 +1,178 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clientAssertionTypeJwtBearer is the `client_assertion_type` used when authenticating using a signed JWT
+const clientAssertionTypeJwtBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// clientCredentialsTokenRequest is a request for a token using the OAuth2 v2 client credentials flow
+type clientCredentialsTokenRequest struct {
+	activeDirectoryEndpoint string
+	clientId                string
+
+	// credential contains the form values used to authenticate the client
+	// e.g. `client_secret` or `client_assertion` and `client_assertion_type`
+	credential url.Values
+
+	scope    string
+	tenantId string
+}
+
+func (r clientCredentialsTokenRequest) execute(ctx context.Context, httpClient *http.Client) (*Token, error) {
+	form := url.Values{
+		"client_id":  []string{r.clientId},
+		"grant_type": []string{"client_credentials"},
+		"scope":      []string{r.scope},
+	}
+	for k, v := range r.credential {
+		form[k] = v
+	}
+
+	tokenUrl := tokenEndpoint(r.activeDirectoryEndpoint, r.tenantId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("building token request: %+v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token from %q: %+v", tokenUrl, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading token response: %+v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseActiveDirectoryError(resp.StatusCode, body)
+	}
+
+	var out clientCredentialsTokenResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("unmarshalling token response: %+v", err)
+	}
+	if out.AccessToken == "" {
+		return nil, fmt.Errorf("token response did not contain an `access_token`")
+	}
+
+	tokenType := out.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return &Token{
+		accessToken: out.AccessToken,
+		expiresOn:   time.Now().Add(time.Duration(out.ExpiresIn) * time.Second),
+		kind:        tokenType,
+	}, nil
+}
+
+type clientCredentialsTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// tokenEndpoint returns the OAuth2 v2 token endpoint for the specified tenant
+func tokenEndpoint(activeDirectoryEndpoint, tenantId string) string {
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(activeDirectoryEndpoint, "/"), tenantId)
+}
+
+// scopeForEndpoint returns the OAuth2 v2 scope granting the default permissions for an endpoint
+// e.g. `https://management.azure.com` becomes `https://management.azure.com/.default`
+func scopeForEndpoint(endpoint string) string {
+	return fmt.Sprintf("%s/.default", strings.TrimSuffix(endpoint, "/"))
+}
+
+// ActiveDirectoryErrorCode is an AADSTS error code returned by Azure Active Directory
+type ActiveDirectoryErrorCode int
+
+const (
+	ApplicationNotFound ActiveDirectoryErrorCode = 700016
+	ExpiredClientSecret ActiveDirectoryErrorCode = 7000222
+	InvalidClientSecret ActiveDirectoryErrorCode = 7000215
+	InvalidResource     ActiveDirectoryErrorCode = 500011
+	InvalidScope        ActiveDirectoryErrorCode = 70011
+	TenantNotFound      ActiveDirectoryErrorCode = 90002
+)
+
+func (c ActiveDirectoryErrorCode) String() string {
+	return fmt.Sprintf("AADSTS%d", int(c))
+}
+
+// ActiveDirectoryError is returned when Azure Active Directory rejects a token request
+type ActiveDirectoryError struct {
+	// StatusCode is the HTTP Status Code returned from the token endpoint
+	StatusCode int
+
+	// Code is the OAuth2 error code, e.g. `invalid_client`
+	Code string
+
+	// Description is the human-readable description of the error
+	Description string
+
+	// ErrorCodes are the AADSTS codes describing this error
+	ErrorCodes []ActiveDirectoryErrorCode
+
+	CorrelationId string
+	TraceId       string
+}
+
+func (e ActiveDirectoryError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("obtaining token from Azure Active Directory: status %d: %s", e.StatusCode, e.Code)
+	}
+
+	return fmt.Sprintf("obtaining token from Azure Active Directory: status %d: %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+// HasErrorCode returns whether this error contains the specified AADSTS error code
+func (e ActiveDirectoryError) HasErrorCode(code ActiveDirectoryErrorCode) bool {
+	for _, v := range e.ErrorCodes {
+		if v == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseActiveDirectoryError(statusCode int, body []byte) error {
+	var out struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		ErrorCodes       []int  `json:"error_codes"`
+		CorrelationId    string `json:"correlation_id"`
+		TraceId          string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil || out.Error == "" {
+		return fmt.Errorf("unexpected status %d obtaining token from Azure Active Directory: %s", statusCode, string(body))
+	}
+
+	codes := make([]ActiveDirectoryErrorCode, 0)
+	for _, v := range out.ErrorCodes {
+		codes = append(codes, ActiveDirectoryErrorCode(v))
+	}
+
+	return ActiveDirectoryError{
+		StatusCode:    statusCode,
+		Code:          out.Error,
+		Description:   out.ErrorDescription,
+		ErrorCodes:    codes,
+		CorrelationId: out.CorrelationId,
+		TraceId:       out.TraceId,
+	}
+}
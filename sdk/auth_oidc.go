@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+// OIDCAssertionFunc returns a federated OIDC token to be exchanged for an Azure Active Directory token
+type OIDCAssertionFunc func(ctx context.Context) (string, error)
+
+type OIDCAuthorizerOptions struct {
+	// ActiveDirectoryEndpoint is the Azure Active Directory endpoint to obtain tokens from
+	// this defaults to `environments.Default` when unspecified
+	ActiveDirectoryEndpoint string
+
+	ClientId string
+	TenantId string
+
+	// Assertion returns the federated token, for example from the GitHub Actions token endpoint
+	// either Assertion or TokenFilePath must be specified
+	Assertion OIDCAssertionFunc
+
+	// TokenFilePath is the path to a file containing the federated token, for example
+	// the file referenced by `AZURE_FEDERATED_TOKEN_FILE` when using Kubernetes Workload Identity
+	TokenFilePath string
+}
+
+// OIDCAuthorizer exchanges a federated OIDC token for an Azure Active Directory token
+type OIDCAuthorizer struct {
+	activeDirectoryEndpoint string
+	assertion               OIDCAssertionFunc
+	clientId                string
+	httpClient              *http.Client
+	tenantId                string
+
+	tokens *tokenCache
+}
+
+func NewOIDCAuthorizer(options OIDCAuthorizerOptions) (Authorizer, error) {
+	if options.ClientId == "" {
+		return nil, fmt.Errorf("`ClientId` must be specified")
+	}
+	if options.TenantId == "" {
+		return nil, fmt.Errorf("`TenantId` must be specified")
+	}
+
+	assertion := options.Assertion
+	if assertion == nil {
+		if options.TokenFilePath == "" {
+			return nil, fmt.Errorf("either `Assertion` or `TokenFilePath` must be specified")
+		}
+		assertion = oidcAssertionFromFile(options.TokenFilePath)
+	}
+
+	activeDirectoryEndpoint := options.ActiveDirectoryEndpoint
+	if activeDirectoryEndpoint == "" {
+		activeDirectoryEndpoint = environments.Default.ActiveDirectoryEndpoint
+	}
+
+	return &OIDCAuthorizer{
+		activeDirectoryEndpoint: activeDirectoryEndpoint,
+		assertion:               assertion,
+		clientId:                options.ClientId,
+		httpClient:              &http.Client{},
+		tenantId:                options.TenantId,
+		tokens:                  newTokenCache(),
+	}, nil
+}
+
+// Token returns a cached token for the specified endpoint, refreshing it when it's close to expiring
+func (a *OIDCAuthorizer) Token(ctx context.Context, endpoint string) (*Token, error) {
+	return a.tokenForTenant(ctx, a.tenantId, endpoint)
+}
+
+// AuxiliaryTokens returns a cached token for the specified endpoint in each of the specified tenants
+func (a *OIDCAuthorizer) AuxiliaryTokens(ctx context.Context, endpoint string, tenantIds []string) ([]*Token, error) {
+	return auxiliaryTokens(ctx, endpoint, tenantIds, a.tokenForTenant)
+}
+
+func (a *OIDCAuthorizer) tokenForTenant(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	return a.tokens.getOrRefresh(ctx, tokenCacheKey(tenantId, endpoint), func(ctx context.Context) (*Token, error) {
+		return a.refreshToken(ctx, tenantId, endpoint)
+	})
+}
+
+func (a *OIDCAuthorizer) refreshToken(ctx context.Context, tenantId, endpoint string) (*Token, error) {
+	// the federated token is retrieved each time since these are short-lived and rotated
+	assertion, err := a.assertion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving federated token: %+v", err)
+	}
+
+	request := clientCredentialsTokenRequest{
+		activeDirectoryEndpoint: a.activeDirectoryEndpoint,
+		clientId:                a.clientId,
+		credential: url.Values{
+			"client_assertion":      []string{assertion},
+			"client_assertion_type": []string{clientAssertionTypeJwtBearer},
+		},
+		scope:    scopeForEndpoint(endpoint),
+		tenantId: tenantId,
+	}
+	return request.execute(ctx, a.httpClient)
+}
+
+func oidcAssertionFromFile(filePath string) OIDCAssertionFunc {
+	return func(ctx context.Context) (string, error) {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("reading %q: %+v", filePath, err)
+		}
+
+		assertion := strings.TrimSpace(string(data))
+		if assertion == "" {
+			return "", fmt.Errorf("%q was empty", filePath)
+		}
+
+		return assertion, nil
+	}
+}
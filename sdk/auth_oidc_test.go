@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOIDCAuthorizerTokenFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		expected := map[string]string{
+			"client_assertion":      "federated-token",
+			"client_assertion_type": clientAssertionTypeJwtBearer,
+			"client_id":             "some-client",
+			"grant_type":            "client_credentials",
+		}
+		for k, v := range expected {
+			if actual := r.PostForm.Get(k); actual != v {
+				t.Errorf("expected %q to be %q but got %q", k, v, actual)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"abc123"}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "pandora")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokenFilePath := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFilePath, []byte("federated-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := NewOIDCAuthorizer(OIDCAuthorizerOptions{
+		ActiveDirectoryEndpoint: server.URL,
+		ClientId:                "some-client",
+		TenantId:                "some-tenant",
+		TokenFilePath:           tokenFilePath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.Token(context.TODO(), "https://management.azure.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := token.AuthorizationHeader(); actual != "Bearer abc123" {
+		t.Fatalf("expected the header to be %q but got %q", "Bearer abc123", actual)
+	}
+}
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenRefreshBuffer is how long before a token expires that we'll request a new one
+const tokenRefreshBuffer = 5 * time.Minute
+
+type tokenRefreshFunc func(ctx context.Context) (*Token, error)
+
+// tokenCache caches tokens by endpoint/audience and is safe for concurrent use
+type tokenCache struct {
+	lock    sync.Mutex
+	entries map[string]*tokenCacheEntry
+}
+
+type tokenCacheEntry struct {
+	// each entry has it's own lock so that tokens for other endpoints can be
+	// retrieved whilst one is being refreshed
+	lock  sync.Mutex
+	token *Token
+}
+
+// tokenCacheKey returns the key used to cache a token for an endpoint within a tenant
+func tokenCacheKey(tenantId, endpoint string) string {
+	return fmt.Sprintf("%s|%s", tenantId, endpoint)
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{
+		entries: make(map[string]*tokenCacheEntry),
+	}
+}
+
+// getOrRefresh returns the cached token for the specified key, calling refresh to obtain
+// a new token if there's no cached token or the cached token is close to expiring
+func (c *tokenCache) getOrRefresh(ctx context.Context, key string, refresh tokenRefreshFunc) (*Token, error) {
+	entry := c.entry(key)
+
+	// holding the lock whilst refreshing means concurrent callers wait for the
+	// in-flight refresh rather than each requesting a new token
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if entry.token != nil && !entry.token.expiresWithin(tokenRefreshBuffer) {
+		return entry.token, nil
+	}
+
+	token, err := refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.token = token
+	return token, nil
+}
+
+func (c *tokenCache) entry(key string) *tokenCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &tokenCacheEntry{}
+		c.entries[key] = entry
+	}
+
+	return entry
+}
@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenCacheReusesValidTokens(t *testing.T) {
+	cache := newTokenCache()
+	var refreshes int32
+	refresh := func(ctx context.Context) (*Token, error) {
+		atomic.AddInt32(&refreshes, 1)
+		return &Token{
+			accessToken: "abc123",
+			expiresOn:   time.Now().Add(time.Hour),
+			kind:        "Bearer",
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.getOrRefresh(context.TODO(), "https://management.azure.com", refresh); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Fatalf("expected 1 refresh but got %d", refreshes)
+	}
+
+	if _, err := cache.getOrRefresh(context.TODO(), "https://vault.azure.net", refresh); err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 2 {
+		t.Fatalf("expected a separate token per endpoint, got %d refreshes", refreshes)
+	}
+}
+
+func TestTokenCacheRefreshesExpiringTokens(t *testing.T) {
+	cache := newTokenCache()
+	var refreshes int32
+	refresh := func(ctx context.Context) (*Token, error) {
+		atomic.AddInt32(&refreshes, 1)
+		return &Token{
+			accessToken: "abc123",
+			expiresOn:   time.Now().Add(tokenRefreshBuffer - time.Second),
+			kind:        "Bearer",
+		}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.getOrRefresh(context.TODO(), "https://management.azure.com", refresh); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if refreshes != 3 {
+		t.Fatalf("expected 3 refreshes but got %d", refreshes)
+	}
+}
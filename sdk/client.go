@@ -5,9 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
 )
 
 type ApiClient interface {
@@ -18,46 +23,162 @@ type ArmResourceId interface {
 	ID(subscriptionId string) string
 }
 
+// BuildResourceManagerURI returns the URI for the specified Resource ID - additional query parameters
+// can be specified using the `QueryParameters` field on the request input
 func BuildResourceManagerURI(id ArmResourceId, subscriptionId, apiVersion string) string {
-	// TODO: support for additional query params
 	return fmt.Sprintf("%s?api-version=%s", id.ID(subscriptionId), apiVersion)
 }
 
+// BuildResourceManagerURIWithSuffix returns the URI for a collection within the specified Resource ID, for example
+// the suffix `/providers/Microsoft.EventHub/namespaces` for a Resource Group ID is used to list the Namespaces within it
+func BuildResourceManagerURIWithSuffix(id ArmResourceId, suffix, subscriptionId, apiVersion string) string {
+	return fmt.Sprintf("%s%s?api-version=%s", id.ID(subscriptionId), suffix, apiVersion)
+}
+
+// ScopedResourceId is a Resource ID which isn't within the Subscription the client is configured for, such as a
+// Management Group, a tenant-level resource or an extension resource (e.g. a Lock) which can be within any scope
+type ScopedResourceId interface {
+	ID() string
+}
+
+// BuildScopedResourceManagerURI returns the URI for the specified Scoped Resource ID - which unlike
+// BuildResourceManagerURI doesn't include the Subscription the client is configured for
+func BuildScopedResourceManagerURI(id ScopedResourceId, apiVersion string) string {
+	return fmt.Sprintf("%s?api-version=%s", id.ID(), apiVersion)
+}
+
+// BuildScopedResourceManagerURIWithSuffix returns the URI for a collection within the specified Scoped Resource ID,
+// for example the suffix `/providers/Microsoft.Authorization/locks` is used to list the Locks within a scope
+func BuildScopedResourceManagerURIWithSuffix(id ScopedResourceId, suffix, apiVersion string) string {
+	// the tenant-root scope is `/`, which mustn't result in a double slash
+	path := fmt.Sprintf("%s%s", strings.TrimSuffix(id.ID(), "/"), suffix)
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s?api-version=%s", path, apiVersion)
+}
+
 type ClientMetaData struct {
 	ResourceProvider *string
 }
 
+// ClientOptions configures the behaviour of a BaseClient
+type ClientOptions struct {
+	// AuxiliaryTenantIds are the tenants to obtain auxiliary tokens for, which are sent in the
+	// `x-ms-authorization-auxiliary` header for cross-tenant requests - ARM supports up to 3
+	AuxiliaryTenantIds []string
+
+	// HttpClient is the HTTP Client used to send requests, defaulting to one using `http.DefaultTransport`
+	HttpClient *http.Client
+
+	// PerCallPolicies are run once for each call, before any retries
+	PerCallPolicies []Policy
+
+	// PollingPolicy configures how frequently Long Running Operations are polled, defaulting to DefaultPollingPolicy
+	PollingPolicy *PollingPolicy
+
+	// PollingTimeout is the maximum amount of time to wait for a Long Running Operation to complete when polling,
+	// after which polling fails with an error wrapping `context.DeadlineExceeded` - by default polling continues
+	// until the operation completes or the context is cancelled
+	PollingTimeout time.Duration
+
+	// PerRetryPolicies are run for each attempt at sending the request, after the request has been retried
+	PerRetryPolicies []Policy
+
+	// RetryPolicy configures how failed requests are retried, defaulting to DefaultRetryPolicy
+	RetryPolicy *RetryPolicy
+
+	// TokenAudience is the audience tokens are requested for, which defaults to the Resource Manager audience
+	// for the Environment - this can be overridden when connecting to data-plane style endpoints
+	TokenAudience string
+}
+
 type BaseClient struct {
-	authorizer Authorizer
-	endpoint   string
-	httpClient *http.Client
+	authorizer         Authorizer
+	auxiliaryTenantIds []string
+	clock              clock
+	endpoint           string
+	httpClient         *http.Client
+	perCallPolicies    []Policy
+	perRetryPolicies   []Policy
+	pollingPolicy      PollingPolicy
+	pollingTimeout     time.Duration
+	retryPolicy        RetryPolicy
+	tokenAudience      string
 }
 
-func DefaultBaseClient(endpoint string, authorizer Authorizer) BaseClient {
-	return BaseClient{
-		authorizer: authorizer,
-		endpoint:   endpoint,
-		httpClient: &http.Client{
+func DefaultBaseClient(environment environments.Environment, authorizer Authorizer) BaseClient {
+	return NewBaseClient(environment, authorizer, ClientOptions{})
+}
+
+// NewBaseClient returns a BaseClient which authorizes requests using the specified Authorizer, which can be nil
+// when requests are instead authorized using one of the PerCallPolicies or PerRetryPolicies
+func NewBaseClient(environment environments.Environment, authorizer Authorizer, options ClientOptions) BaseClient {
+	tokenAudience := options.TokenAudience
+	if tokenAudience == "" {
+		tokenAudience = environment.ResourceManagerAudience
+	}
+
+	pollingPolicy := DefaultPollingPolicy()
+	if options.PollingPolicy != nil {
+		pollingPolicy = *options.PollingPolicy
+	}
+
+	retryPolicy := DefaultRetryPolicy()
+	if options.RetryPolicy != nil {
+		retryPolicy = *options.RetryPolicy
+	}
+
+	httpClient := options.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
 			Transport: http.DefaultTransport,
-		},
+		}
+	}
+
+	return BaseClient{
+		authorizer:         authorizer,
+		auxiliaryTenantIds: options.AuxiliaryTenantIds,
+		clock:              systemClock{},
+		endpoint:           strings.TrimSuffix(environment.ResourceManagerEndpoint, "/"),
+		httpClient:         httpClient,
+		perCallPolicies:    options.PerCallPolicies,
+		perRetryPolicies:   options.PerRetryPolicies,
+		pollingPolicy:      pollingPolicy,
+		pollingTimeout:     options.PollingTimeout,
+		retryPolicy:        retryPolicy,
+		tokenAudience:      tokenAudience,
 	}
 }
 
 type DeleteHttpRequestInput struct {
 	ExpectedStatusCodes []int
-	Uri                 string
+
+	// Headers are additional headers to send with the request, for example `If-Match`
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request, for example `$expand`,
+	// which are escaped and appended to the Uri
+	QueryParameters map[string]string
+
+	Uri string
 }
 
 func (c BaseClient) Delete(ctx context.Context, input DeleteHttpRequestInput) (*http.Response, error) {
-	url := fmt.Sprintf("https://%s%s", c.endpoint, input.Uri)
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
+	if err != nil {
+		return nil, fmt.Errorf("building uri: %+v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, *url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("building request: %+v", err)
 	}
+	setHeaders(req, input.Headers)
 
 	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
 	if err != nil {
-		return nil, fmt.Errorf("making request: %+v", err)
+		return nil, fmt.Errorf("making request: %w", err)
 	}
 
 	return resp, nil
@@ -66,7 +187,7 @@ func (c BaseClient) Delete(ctx context.Context, input DeleteHttpRequestInput) (*
 func (c BaseClient) DeleteThenPoll(ctx context.Context, input DeleteHttpRequestInput) (Poller, error) {
 	originalResp, err := c.Delete(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %+v", err)
+		return nil, fmt.Errorf("sending Request: %w", err)
 	}
 
 	poller, err := DeterminePoller(originalResp, &c, input.Uri)
@@ -79,11 +200,19 @@ func (c BaseClient) DeleteThenPoll(ctx context.Context, input DeleteHttpRequestI
 
 type GetHttpRequestInput struct {
 	ExpectedStatusCodes []int
-	Uri                 string
+
+	// Headers are additional headers to send with the request, for example `If-Match`
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request, for example `$expand`,
+	// which are escaped and appended to the Uri
+	QueryParameters map[string]string
+
+	Uri string
 }
 
 func (c BaseClient) Get(ctx context.Context, input GetHttpRequestInput) (*http.Response, error) {
-	url, err := c.buildUri(input.Uri)
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
 	if err != nil {
 		return nil, fmt.Errorf("building uri: %+v", err)
 	}
@@ -92,17 +221,18 @@ func (c BaseClient) Get(ctx context.Context, input GetHttpRequestInput) (*http.R
 	if err != nil {
 		return nil, fmt.Errorf("building request: %+v", err)
 	}
+	setHeaders(req, input.Headers)
 
 	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
 	if err != nil {
-		return resp, fmt.Errorf("making request: %+v", err)
+		return resp, fmt.Errorf("making request: %w", err)
 	}
 
 	return resp, nil
 }
 
 func (c BaseClient) GetJson(ctx context.Context, input GetHttpRequestInput, out interface{}) (*http.Response, error) {
-	url, err := c.buildUri(input.Uri)
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
 	if err != nil {
 		return nil, fmt.Errorf("building uri: %+v", err)
 	}
@@ -111,17 +241,23 @@ func (c BaseClient) GetJson(ctx context.Context, input GetHttpRequestInput, out
 	if err != nil {
 		return nil, fmt.Errorf("building request: %+v", err)
 	}
+	setHeaders(req, input.Headers)
 
 	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
 	if err != nil {
-		return resp, fmt.Errorf("making request: %+v", err)
+		return resp, fmt.Errorf("making request: %w", err)
 	}
 
 	if contentType := resp.Header.Get("Content-Type"); !strings.Contains(contentType, "application/json") {
 		return resp, fmt.Errorf("expected the 'Content-Type' to be 'application/json' but got %q", contentType)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+	// the body is buffered so that it can be read again, for example to obtain the `etag` using ETagFromResponse
+	body, err := readResponseBody(resp)
+	if err != nil {
+		return resp, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
 		return resp, fmt.Errorf("unmarshalling response: %+v", err)
 	}
 
@@ -131,7 +267,15 @@ func (c BaseClient) GetJson(ctx context.Context, input GetHttpRequestInput, out
 type PatchHttpRequestInput struct {
 	Body                interface{}
 	ExpectedStatusCodes []int
-	Uri                 string
+
+	// Headers are additional headers to send with the request, for example `If-Match`
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request, for example `$expand`,
+	// which are escaped and appended to the Uri
+	QueryParameters map[string]string
+
+	Uri string
 }
 
 func (c BaseClient) PatchJson(ctx context.Context, input PatchHttpRequestInput) (*http.Response, error) {
@@ -140,7 +284,7 @@ func (c BaseClient) PatchJson(ctx context.Context, input PatchHttpRequestInput)
 		return nil, fmt.Errorf("marshalling body: %+v", err)
 	}
 
-	url, err := c.buildUri(input.Uri)
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
 	if err != nil {
 		return nil, fmt.Errorf("building uri: %+v", err)
 	}
@@ -148,6 +292,7 @@ func (c BaseClient) PatchJson(ctx context.Context, input PatchHttpRequestInput)
 	if err != nil {
 		return nil, fmt.Errorf("building request: %+v", err)
 	}
+	setHeaders(req, input.Headers)
 
 	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
 	if err != nil {
@@ -160,7 +305,88 @@ func (c BaseClient) PatchJson(ctx context.Context, input PatchHttpRequestInput)
 func (c BaseClient) PatchJsonThenPoll(ctx context.Context, input PatchHttpRequestInput) (Poller, error) {
 	originalResp, err := c.PatchJson(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %+v", err)
+		return nil, fmt.Errorf("sending Request: %w", err)
+	}
+
+	poller, err := DeterminePoller(originalResp, &c, input.Uri)
+	if err != nil {
+		return nil, fmt.Errorf("building poller: %+v", err)
+	}
+
+	return poller, nil
+}
+
+type PostHttpRequestInput struct {
+	// Body is the (optional) request body, which is sent as JSON when specified
+	Body                interface{}
+	ExpectedStatusCodes []int
+
+	// Headers are additional headers to send with the request, for example `If-Match`
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request, for example `$expand`,
+	// which are escaped and appended to the Uri
+	QueryParameters map[string]string
+
+	Uri string
+}
+
+// PostJson performs a POST request, typically against an action such as `listKeys` - when `out` is
+// specified the response body (if any) is unmarshalled into it
+func (c BaseClient) PostJson(ctx context.Context, input PostHttpRequestInput, out interface{}) (*http.Response, error) {
+	var body io.Reader
+	if input.Body != nil {
+		marshalledBytes, err := json.Marshal(input.Body)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling body: %+v", err)
+		}
+		body = bytes.NewReader(marshalledBytes)
+	}
+
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
+	if err != nil {
+		return nil, fmt.Errorf("building uri: %+v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *url, body)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %+v", err)
+	}
+	setHeaders(req, input.Headers)
+
+	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
+	if err != nil {
+		return resp, err
+	}
+
+	// actions can return a 200 without a body, in which case there's no result
+	if out == nil || resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
+		return resp, nil
+	}
+
+	// the Content-Length isn't always known, so the body is buffered to check whether it's empty
+	respBody, err := readResponseBody(resp)
+	if err != nil {
+		return resp, err
+	}
+	if len(respBody) == 0 {
+		return resp, nil
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); !strings.Contains(contentType, "application/json") {
+		return resp, fmt.Errorf("expected the 'Content-Type' to be 'application/json' but got %q", contentType)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return resp, fmt.Errorf("unmarshalling response: %+v", err)
+	}
+
+	return resp, nil
+}
+
+func (c BaseClient) PostJsonThenPoll(ctx context.Context, input PostHttpRequestInput) (Poller, error) {
+	originalResp, err := c.PostJson(ctx, input, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sending Request: %w", err)
 	}
 
 	poller, err := DeterminePoller(originalResp, &c, input.Uri)
@@ -174,7 +400,15 @@ func (c BaseClient) PatchJsonThenPoll(ctx context.Context, input PatchHttpReques
 type PutHttpRequestInput struct {
 	Body                interface{}
 	ExpectedStatusCodes []int
-	Uri                 string
+
+	// Headers are additional headers to send with the request, for example `If-Match`
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request, for example `$expand`,
+	// which are escaped and appended to the Uri
+	QueryParameters map[string]string
+
+	Uri string
 }
 
 func (c BaseClient) PutJson(ctx context.Context, input PutHttpRequestInput) (*http.Response, error) {
@@ -183,7 +417,7 @@ func (c BaseClient) PutJson(ctx context.Context, input PutHttpRequestInput) (*ht
 		return nil, fmt.Errorf("marshalling body: %+v", err)
 	}
 
-	url, err := c.buildUri(input.Uri)
+	url, err := c.buildUri(input.Uri, input.QueryParameters)
 	if err != nil {
 		return nil, fmt.Errorf("building uri: %+v", err)
 	}
@@ -191,6 +425,7 @@ func (c BaseClient) PutJson(ctx context.Context, input PutHttpRequestInput) (*ht
 	if err != nil {
 		return nil, fmt.Errorf("building request: %+v", err)
 	}
+	setHeaders(req, input.Headers)
 
 	resp, err := c.performAuthenticatedHttpRequest(ctx, req, input.ExpectedStatusCodes)
 	if err != nil {
@@ -203,7 +438,7 @@ func (c BaseClient) PutJson(ctx context.Context, input PutHttpRequestInput) (*ht
 func (c BaseClient) PutJsonThenPoll(ctx context.Context, input PutHttpRequestInput) (Poller, error) {
 	originalResp, err := c.PutJson(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("sending Request: %+v", err)
+		return nil, fmt.Errorf("sending Request: %w", err)
 	}
 
 	poller, err := DeterminePoller(originalResp, &c, input.Uri)
@@ -215,41 +450,88 @@ func (c BaseClient) PutJsonThenPoll(ctx context.Context, input PutHttpRequestInp
 }
 
 func (c BaseClient) performAuthenticatedHttpRequest(ctx context.Context, req *http.Request, expectedStatusCodes []int) (*http.Response, error) {
-	token, err := c.authorizer.Token(ctx, "https://management.azure.com")
-	if err != nil {
-		return nil, fmt.Errorf("retrieving auth token: %+v", err)
-	}
-	req.Header.Add("Authorization", token.AuthorizationHeader())
-	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	// the expected status codes are made available to the pipeline so that these aren't retried
+	req = req.WithContext(withExpectedStatusCodes(ctx, expectedStatusCodes))
 
-	// TODO: handle retries, 429's etc
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.pipeline().Do(req)
 	if err != nil {
 		return resp, fmt.Errorf("sending request: %+v", err)
 	}
 
 	if exists := containsStatusCode(expectedStatusCodes, resp.StatusCode); !exists {
-		return resp, fmt.Errorf("unexpected status %d (%s)", resp.StatusCode, resp.Status)
+		return resp, newResponseError(resp)
 	}
 
 	return resp, nil
 }
 
-func (c BaseClient) buildUri(input string) (*string, error) {
+// pipeline returns the Pipeline used to send requests, which runs (in order) the per-call policies,
+// the retry policy, the per-retry policies and then authorizes the request before sending it - unless the
+// Authorizer is nil, in which case the request is expected to be authorized by a per-call or per-retry policy
+func (c BaseClient) pipeline() Pipeline {
+	policies := make([]Policy, 0)
+	policies = append(policies, c.perCallPolicies...)
+	policies = append(policies, retryingPolicy{
+		clock:  c.clock,
+		policy: c.retryPolicy,
+	})
+	policies = append(policies, c.perRetryPolicies...)
+	if c.authorizer != nil {
+		policies = append(policies, authorizationPolicy{
+			authorizer:         c.authorizer,
+			auxiliaryTenantIds: c.auxiliaryTenantIds,
+			tokenAudience:      c.tokenAudience,
+		})
+	}
+
+	return NewPipeline(c.httpClient, policies...)
+}
+
+func (c BaseClient) buildUri(input string, queryParameters map[string]string) (*string, error) {
 	uri, err := url.Parse(input)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(queryParameters) > 0 {
+		query := uri.Query()
+		for k, v := range queryParameters {
+			query.Set(k, v)
+		}
+		uri.RawQuery = query.Encode()
+		input = uri.String()
+	}
+
 	// it's a full URI so let's use it
 	if uri.IsAbs() {
 		return &input, nil
 	}
 
-	output := fmt.Sprintf("https://%s%s", c.endpoint, input)
+	output := fmt.Sprintf("%s%s", c.endpoint, input)
 	return &output, nil
 }
 
+// readResponseBody reads the body of the response, which is then replaced so that it can be read again
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %+v", err)
+	}
+
+	return body, nil
+}
+
+// setHeaders sets the additional headers specified on the request input
+func setHeaders(req *http.Request, headers map[string]string) {
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+}
+
 func containsStatusCode(expected []int, actual int) bool {
 	for _, v := range expected {
 		if actual == v {
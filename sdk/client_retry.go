@@ -0,0 +1,237 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how requests which are throttled or fail transiently are retried
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the initial attempt
+	// a value of 1 disables retries
+	MaxAttempts int
+
+	// MinDelay is the delay before the first retry when the response doesn't contain a `Retry-After`
+	// header, which doubles (with jitter) for each subsequent retry
+	MinDelay time.Duration
+
+	// MaxDelay is the maximum delay between retries when the response doesn't contain a `Retry-After` header
+	MaxDelay time.Duration
+
+	// StatusCodes are the HTTP Status Codes which are retried - however since `POST` requests aren't
+	// idempotent these are only retried when throttled (429) or when a 503 contains a `Retry-After` header
+	StatusCodes []int
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used when one isn't specified in the ClientOptions
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinDelay:    1 * time.Second,
+		MaxDelay:    60 * time.Second,
+		StatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// retryingPolicy is the Policy which retries requests according to the RetryPolicy
+type retryingPolicy struct {
+	clock  clock
+	policy RetryPolicy
+}
+
+func (p retryingPolicy) Do(req *http.Request, next NextPolicy) (*http.Response, error) {
+	ctx := req.Context()
+	expectedStatusCodes := expectedStatusCodesFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := next(req)
+		if !p.shouldRetry(req, attempt, resp, err, expectedStatusCodes) {
+			return resp, err
+		}
+
+		delay := p.policy.delayBeforeRetry(attempt, resp, p.clock.Now())
+		if !p.canWait(ctx, delay) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// the connection can only be reused once the body has been read
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting to retry request: %+v", ctx.Err())
+		case <-p.clock.After(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewinding request body: %+v", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// shouldRetry returns whether the request should be sent again based on the response/error from the latest attempt
+func (p retryingPolicy) shouldRetry(req *http.Request, attempt int, resp *http.Response, err error, expectedStatusCodes []int) bool {
+	if attempt >= p.policy.MaxAttempts || req.Context().Err() != nil {
+		return false
+	}
+
+	// a request body which has been sent can't be sent again unless it can be rewound
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	// non-idempotent requests (e.g. an action such as `restart`) may have been processed when the connection fails
+	// or the server returns an error - so these are only retried when it's known they weren't processed
+	idempotent := isIdempotentMethod(req.Method)
+
+	if err != nil {
+		if !idempotent {
+			return isConnectionRefusedError(err)
+		}
+		return isTransientNetworkError(err)
+	}
+
+	if containsStatusCode(expectedStatusCodes, resp.StatusCode) || !containsStatusCode(p.policy.StatusCodes, resp.StatusCode) {
+		return false
+	}
+
+	if !idempotent {
+		return wasNotProcessed(resp)
+	}
+	return true
+}
+
+// isIdempotentMethod returns whether sending a request using the specified HTTP Method more than once has
+// the same effect as sending it once - which isn't the case for `POST` requests (e.g. actions such as `restart`),
+// whereas a `PATCH` to an ARM resource sets the same properties each time
+func isIdempotentMethod(method string) bool {
+	return method != http.MethodPost
+}
+
+// wasNotProcessed returns whether the response indicates the server didn't process the request, which is
+// the case when the request was throttled or the server is unavailable and has asked the client to retry
+func wasNotProcessed(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != ""
+}
+
+// canWait returns whether there's enough time left before the context's deadline to wait for the specified delay
+func (p retryingPolicy) canWait(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+
+	return p.clock.Now().Add(delay).Before(deadline)
+}
+
+// delayBeforeRetry returns how long to wait before retrying - using the `Retry-After` header when it's
+// present, otherwise using exponential backoff with jitter
+func (p RetryPolicy) delayBeforeRetry(attempt int, resp *http.Response, now time.Time) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return delay
+		}
+	}
+
+	delay := p.MinDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// jitter between 50% and 100% of the delay, so that many clients throttled at the same time don't retry in lockstep
+	if half := int64(delay / 2); half > 0 {
+		delay = time.Duration(half + rand.Int63n(half+1))
+	}
+
+	return delay
+}
+
+// maxRetryAfter is the longest delay a `Retry-After` header can request, so that a misbehaving server
+// can't stall a request indefinitely
+const maxRetryAfter = 5 * time.Minute
+
+// parseRetryAfter parses the value of a `Retry-After` header, which is either a number of seconds or an HTTP-date
+// the delay is capped at maxRetryAfter
+func parseRetryAfter(input string, now time.Time) (time.Duration, bool) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(input, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(maxRetryAfter/time.Second) {
+			return maxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(input); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		if delay > maxRetryAfter {
+			delay = maxRetryAfter
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// isConnectionRefusedError returns whether the connection was refused, in which case the request was never sent
+func isConnectionRefusedError(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isTransientNetworkError returns whether the error is a network error which is likely to succeed when retried
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
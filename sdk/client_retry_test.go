@@ -0,0 +1,301 @@
+package sdk
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(duration time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, duration)
+	c.now = c.now.Add(duration)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func newTestBaseClient(clock *fakeClock) BaseClient {
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	client.clock = clock
+	return client
+}
+
+func TestRetryHonoursRetryAfterSeconds(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	if _, err := client.Get(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests but got %d", requests)
+	}
+	if len(clock.delays) != 1 || clock.delays[0] != 7*time.Second {
+		t.Fatalf("expected a single delay of 7s but got %+v", clock.delays)
+	}
+}
+
+func TestRetryBacksOffAndRewindsBody(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"location":"westeurope"}` {
+			t.Errorf("unexpected body for attempt %d: %q", requests, string(body))
+		}
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	input := PutHttpRequestInput{
+		Body: map[string]string{
+			"location": "westeurope",
+		},
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	if _, err := client.PutJson(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 3 {
+		t.Fatalf("expected 3 requests but got %d", requests)
+	}
+	if len(clock.delays) != 2 {
+		t.Fatalf("expected 2 delays but got %+v", clock.delays)
+	}
+	if clock.delays[0] < 500*time.Millisecond || clock.delays[0] > time.Second {
+		t.Fatalf("expected the first delay to be between 0.5s and 1s but got %s", clock.delays[0])
+	}
+	if clock.delays[1] < time.Second || clock.delays[1] > 2*time.Second {
+		t.Fatalf("expected the second delay to be between 1s and 2s but got %s", clock.delays[1])
+	}
+}
+
+func TestRetryNonIdempotentRequests(t *testing.T) {
+	testData := []struct {
+		method     string
+		statusCode int
+		retryAfter string
+		requests   int
+	}{
+		{
+			// the server may have processed the request before failing
+			method:     http.MethodPost,
+			statusCode: http.StatusInternalServerError,
+			requests:   1,
+		},
+		{
+			// whereas the server explicitly didn't process the request
+			method:     http.MethodPost,
+			statusCode: http.StatusTooManyRequests,
+			requests:   2,
+		},
+		{
+			method:     http.MethodPost,
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: "1",
+			requests:   2,
+		},
+		{
+			// idempotent requests can always be retried
+			method:     http.MethodPut,
+			statusCode: http.StatusInternalServerError,
+			requests:   2,
+		},
+		{
+			method:     http.MethodPatch,
+			statusCode: http.StatusServiceUnavailable,
+			requests:   2,
+		},
+	}
+	for _, v := range testData {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				if v.retryAfter != "" {
+					w.Header().Set("Retry-After", v.retryAfter)
+				}
+				w.WriteHeader(v.statusCode)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		client := newTestBaseClient(&fakeClock{now: time.Now()})
+		req, err := http.NewRequestWithContext(context.TODO(), v.method, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.performAuthenticatedHttpRequest(context.TODO(), req, []int{http.StatusOK})
+		server.Close()
+
+		if requests != v.requests {
+			t.Fatalf("expected %d requests for a %s returning %d but got %d", v.requests, v.method, v.statusCode, requests)
+		}
+	}
+}
+
+func TestRetryNonIdempotentRequestsOnNetworkErrors(t *testing.T) {
+	policy := retryingPolicy{
+		clock:  &fakeClock{now: time.Now()},
+		policy: DefaultRetryPolicy(),
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://management.azure.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the request may have been received before the connection was reset
+	if policy.shouldRetry(req, 1, nil, syscall.ECONNRESET, nil) {
+		t.Fatal("expected a POST not to be retried when the connection was reset")
+	}
+	if !policy.shouldRetry(req, 1, nil, syscall.ECONNREFUSED, nil) {
+		t.Fatal("expected a POST to be retried when the connection was refused")
+	}
+
+	req, err = http.NewRequest(http.MethodPatch, "https://management.azure.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.shouldRetry(req, 1, nil, syscall.ECONNRESET, nil) {
+		t.Fatal("expected a PATCH to be retried when the connection was reset")
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	resp, err := client.Get(context.TODO(), input)
+	if err == nil {
+		t.Fatal("expected an error but didn't get one")
+	}
+	if resp == nil || resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the last response to be returned")
+	}
+	if expected := DefaultRetryPolicy().MaxAttempts; requests != expected {
+		t.Fatalf("expected %d requests but got %d", expected, requests)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	if _, err := client.Get(ctx, input); err == nil {
+		t.Fatal("expected an error but didn't get one")
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected 1 request but got %d", requests)
+	}
+	if len(clock.delays) != 0 {
+		t.Fatalf("expected no delays but got %+v", clock.delays)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	testData := []struct {
+		input    string
+		expected time.Duration
+		valid    bool
+	}{
+		{
+			input: "",
+		},
+		{
+			input:    "30",
+			expected: 30 * time.Second,
+			valid:    true,
+		},
+		{
+			input:    "Wed, 01 Jan 2020 12:01:30 GMT",
+			expected: 90 * time.Second,
+			valid:    true,
+		},
+		{
+			input: "soon",
+		},
+		{
+			// a server can't stall the request indefinitely
+			input:    "86400",
+			expected: maxRetryAfter,
+			valid:    true,
+		},
+		{
+			input:    "Thu, 02 Jan 2020 12:00:00 GMT",
+			expected: maxRetryAfter,
+			valid:    true,
+		},
+	}
+	for _, v := range testData {
+		actual, valid := parseRetryAfter(v.input, now)
+		if valid != v.valid || actual != v.expected {
+			t.Fatalf("expected %q to be %s (%t) but got %s (%t)", v.input, v.expected, v.valid, actual, valid)
+		}
+	}
+}
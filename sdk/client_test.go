@@ -0,0 +1,234 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+func TestBaseClientSendsAuxiliaryTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actual := r.Header.Get("Authorization"); actual != "Bearer abc123" {
+			t.Errorf("expected the `Authorization` header to be %q but got %q", "Bearer abc123", actual)
+		}
+
+		expected := "Bearer tenant-1, Bearer tenant-2"
+		if actual := r.Header.Get("x-ms-authorization-auxiliary"); actual != expected {
+			t.Errorf("expected the `x-ms-authorization-auxiliary` header to be %q but got %q", expected, actual)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{
+		AuxiliaryTenantIds: []string{"tenant-1", "tenant-2"},
+	})
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL + "/subscriptions/123",
+	}
+	if _, err := client.Get(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaseClientTokenAudience(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testData := []struct {
+		environment environments.Environment
+		options     ClientOptions
+		expected    string
+	}{
+		{
+			environment: environments.Public,
+			expected:    "https://management.azure.com",
+		},
+		{
+			environment: environments.China,
+			expected:    "https://management.chinacloudapi.cn",
+		},
+		{
+			environment: environments.USGovernment,
+			expected:    "https://management.usgovcloudapi.net",
+		},
+		{
+			environment: environments.Public,
+			options: ClientOptions{
+				TokenAudience: "https://storage.azure.com",
+			},
+			expected: "https://storage.azure.com",
+		},
+	}
+	for _, v := range testData {
+		authorizer := &fakeAuthorizer{}
+		client := NewBaseClient(v.environment, authorizer, v.options)
+		input := GetHttpRequestInput{
+			ExpectedStatusCodes: []int{http.StatusOK},
+			Uri:                 server.URL,
+		}
+		if _, err := client.Get(context.TODO(), input); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(authorizer.endpoints) != 1 || authorizer.endpoints[0] != v.expected {
+			t.Fatalf("expected a token to be requested for %q but got %+v", v.expected, authorizer.endpoints)
+		}
+	}
+}
+
+func TestBaseClientPostJson(t *testing.T) {
+	type keys struct {
+		PrimaryKey string `json:"primaryKey"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected a POST request but got %q", r.Method)
+		}
+
+		if r.URL.Path == "/regenerateKeys" {
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding request body: %+v", err)
+			}
+			if body["keyType"] != "PrimaryKey" {
+				t.Errorf("expected the `keyType` to be %q but got %q", "PrimaryKey", body["keyType"])
+			}
+		} else if r.ContentLength != 0 {
+			t.Errorf("expected no request body for %q but got %d bytes", r.URL.Path, r.ContentLength)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"primaryKey":"abc123"}`))
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+
+	var out keys
+	input := PostHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL + "/listKeys",
+	}
+	if _, err := client.PostJson(context.TODO(), input, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.PrimaryKey != "abc123" {
+		t.Fatalf("expected the `primaryKey` to be %q but got %q", "abc123", out.PrimaryKey)
+	}
+
+	input = PostHttpRequestInput{
+		Body: map[string]string{
+			"keyType": "PrimaryKey",
+		},
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL + "/regenerateKeys",
+	}
+	if _, err := client.PostJson(context.TODO(), input, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaseClientPostJsonWithoutResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// chunked, so that the Content-Length isn't known
+		if r.URL.Path == "/chunked" {
+			w.(http.Flusher).Flush()
+			return
+		}
+
+		w.Header().Set("Content-Length", "0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	for _, path := range []string{"/restart", "/chunked"} {
+		var out map[string]string
+		input := PostHttpRequestInput{
+			ExpectedStatusCodes: []int{http.StatusOK},
+			Uri:                 server.URL + path,
+		}
+		if _, err := client.PostJson(context.TODO(), input, &out); err != nil {
+			t.Fatalf("expected no error for an empty response body from %q but got %+v", path, err)
+		}
+		if out != nil {
+			t.Fatalf("expected no result for %q but got %+v", path, out)
+		}
+	}
+}
+
+func TestBaseClientSendsQueryParametersAndHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actual := r.URL.Query().Get("api-version"); actual != "2020-06-01" {
+			t.Errorf("expected the `api-version` to be %q but got %q", "2020-06-01", actual)
+		}
+		if actual := r.URL.Query().Get("$expand"); actual != "properties/a b&c" {
+			t.Errorf("expected `$expand` to be %q but got %q", "properties/a b&c", actual)
+		}
+		if actual := r.URL.Query().Get("forceDeletionTypes"); actual != "Microsoft.Compute/virtualMachines" {
+			t.Errorf("expected `forceDeletionTypes` to be %q but got %q", "Microsoft.Compute/virtualMachines", actual)
+		}
+		if actual := r.Header.Get("x-ms-client-request-id"); actual != "abc123" {
+			t.Errorf("expected the `x-ms-client-request-id` header to be %q but got %q", "abc123", actual)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	input := DeleteHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Headers: map[string]string{
+			"x-ms-client-request-id": "abc123",
+		},
+		QueryParameters: map[string]string{
+			"$expand":            "properties/a b&c",
+			"forceDeletionTypes": "Microsoft.Compute/virtualMachines",
+		},
+		Uri: server.URL + "/subscriptions/123?api-version=2020-06-01",
+	}
+	if _, err := client.Delete(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildScopedResourceManagerURI(t *testing.T) {
+	id := NewScopeID("/subscriptions/1234/resourceGroups/example/")
+	expected := "/subscriptions/1234/resourceGroups/example/providers/Microsoft.Authorization/locks?api-version=2016-09-01"
+	if actual := BuildScopedResourceManagerURIWithSuffix(id, "/providers/Microsoft.Authorization/locks", "2016-09-01"); actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+
+	// the Subscription the client is configured for isn't injected into Scoped Resource IDs
+	expected = "/subscriptions/1234/resourceGroups/example?api-version=2016-09-01"
+	if actual := BuildScopedResourceManagerURI(id, "2016-09-01"); actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+	testData := map[string]string{
+		// the tenant-root scope
+		"/": "/providers/Microsoft.Authorization/roleAssignments?api-version=2016-09-01",
+		"":  "/providers/Microsoft.Authorization/roleAssignments?api-version=2016-09-01",
+		"/providers/Microsoft.Management/managementGroups/group1": "/providers/Microsoft.Management/managementGroups/group1/providers/Microsoft.Authorization/roleAssignments?api-version=2016-09-01",
+	}
+	for scope, expected := range testData {
+		actual := BuildScopedResourceManagerURIWithSuffix(NewScopeID(scope), "/providers/Microsoft.Authorization/roleAssignments", "2016-09-01")
+		if actual != expected {
+			t.Fatalf("expected %q for the scope %q but got %q", expected, scope, actual)
+		}
+	}
+
+	expected = "/?api-version=2016-09-01"
+	if actual := BuildScopedResourceManagerURI(NewScopeID("/"), "2016-09-01"); actual != expected {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+}
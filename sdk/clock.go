@@ -0,0 +1,19 @@
+package sdk
+
+import "time"
+
+// clock provides the current time and timers, allowing time to be faked in tests
+type clock interface {
+	Now() time.Time
+	After(duration time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
@@ -1,8 +0,0 @@
-package endpoints
-
-const DefaultActiveDirectoryEndpoint = AzurePublicActiveDirectoryEndpoint
-
-const AzureChinaActiveDirectoryEndpoint = "https://login.chinacloudapi.cn/"
-const AzureGermanyActiveDirectoryEndpoint = "https://login.microsoftonline.de/"
-const AzurePublicActiveDirectoryEndpoint = "https://login.microsoftonline.com"
-const AzureUSGovernmentActiveDirectoryEndpoint = "https://login.microsoftonline.us/"
\ No newline at end of file
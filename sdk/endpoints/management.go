@@ -1,8 +0,0 @@
-package endpoints
-
-const DefaultManagementEndpoint = AzurePublicManagementEndpoint
-
-const AzureChinaManagementEndpoint = "management.chinacloudapi.cn"
-const AzureGermanyManagementEndpoint = "management.microsoftazure.de"
-const AzurePublicManagementEndpoint = "management.azure.com"
-const AzureUSGovernmentManagementEndpoint = "management.chinacloudapi.cn"
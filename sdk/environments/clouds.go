@@ -0,0 +1,43 @@
+package environments
+
+// Default is the Environment used when none is specified
+var Default = Public
+
+var Public = Environment{
+	Name:                    "AzureCloud",
+	ActiveDirectoryEndpoint: "https://login.microsoftonline.com",
+	ResourceManagerEndpoint: "https://management.azure.com",
+	ResourceManagerAudience: "https://management.azure.com",
+	Suffixes: Suffixes{
+		ContainerRegistry: "azurecr.io",
+		KeyVault:          "vault.azure.net",
+		SqlServer:         "database.windows.net",
+		Storage:           "core.windows.net",
+	},
+}
+
+var China = Environment{
+	Name:                    "AzureChinaCloud",
+	ActiveDirectoryEndpoint: "https://login.chinacloudapi.cn",
+	ResourceManagerEndpoint: "https://management.chinacloudapi.cn",
+	ResourceManagerAudience: "https://management.chinacloudapi.cn",
+	Suffixes: Suffixes{
+		ContainerRegistry: "azurecr.cn",
+		KeyVault:          "vault.azure.cn",
+		SqlServer:         "database.chinacloudapi.cn",
+		Storage:           "core.chinacloudapi.cn",
+	},
+}
+
+var USGovernment = Environment{
+	Name:                    "AzureUSGovernment",
+	ActiveDirectoryEndpoint: "https://login.microsoftonline.us",
+	ResourceManagerEndpoint: "https://management.usgovcloudapi.net",
+	ResourceManagerAudience: "https://management.usgovcloudapi.net",
+	Suffixes: Suffixes{
+		ContainerRegistry: "azurecr.us",
+		KeyVault:          "vault.usgovcloudapi.net",
+		SqlServer:         "database.usgovcloudapi.net",
+		Storage:           "core.usgovcloudapi.net",
+	},
+}
@@ -0,0 +1,58 @@
+package environments
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment describes the endpoints and suffixes used by an Azure Cloud
+type Environment struct {
+	// Name is the name of this Environment, e.g. `AzureCloud`
+	Name string
+
+	// ActiveDirectoryEndpoint is the Azure Active Directory authority, e.g. `https://login.microsoftonline.com`
+	ActiveDirectoryEndpoint string
+
+	// ResourceManagerEndpoint is the Resource Manager endpoint, e.g. `https://management.azure.com`
+	ResourceManagerEndpoint string
+
+	// ResourceManagerAudience is the audience tokens are requested for when calling Resource Manager
+	ResourceManagerAudience string
+
+	Suffixes Suffixes
+}
+
+// Suffixes are the DNS suffixes used by resource-specific endpoints within an Environment
+type Suffixes struct {
+	// ContainerRegistry is the suffix for Container Registry login servers, e.g. `azurecr.io`
+	ContainerRegistry string
+
+	// KeyVault is the suffix for Key Vaults, e.g. `vault.azure.net`
+	KeyVault string
+
+	// SqlServer is the suffix for SQL Servers, e.g. `database.windows.net`
+	SqlServer string
+
+	// Storage is the suffix for Storage Accounts, e.g. `core.windows.net`
+	Storage string
+}
+
+// FromName returns the built-in Environment with the specified name, which can either be the
+// name used by Azure (e.g. `AzureChinaCloud`) or a short-hand name (e.g. `china`)
+func FromName(name string) (*Environment, error) {
+	switch strings.ToLower(name) {
+	case "azurecloud", "public":
+		env := Public
+		return &env, nil
+
+	case "azurechinacloud", "china":
+		env := China
+		return &env, nil
+
+	case "azureusgovernment", "azureusgovernmentcloud", "usgovernment":
+		env := USGovernment
+		return &env, nil
+	}
+
+	return nil, fmt.Errorf("no built-in environment was found named %q", name)
+}
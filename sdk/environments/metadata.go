@@ -0,0 +1,138 @@
+package environments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// metadataApiVersion is the API Version used to retrieve the `metadata/endpoints` document
+const metadataApiVersion = "2022-09-01"
+
+// FromEndpoint retrieves the Environment for a Resource Manager endpoint (for example an Azure Stack Hub)
+// from it's `metadata/endpoints` document
+func FromEndpoint(ctx context.Context, resourceManagerEndpoint, name string) (*Environment, error) {
+	uri := fmt.Sprintf("%s/metadata/endpoints?api-version=%s", strings.TrimSuffix(resourceManagerEndpoint, "/"), metadataApiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %+v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving %q: %+v", uri, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %+v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d retrieving %q: %s", resp.StatusCode, uri, string(data))
+	}
+
+	return FromMetadata(data, resourceManagerEndpoint, name)
+}
+
+// FromFile loads the Environment from a `metadata/endpoints` document stored in a local file
+func FromFile(filePath, resourceManagerEndpoint, name string) (*Environment, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %+v", filePath, err)
+	}
+
+	return FromMetadata(data, resourceManagerEndpoint, name)
+}
+
+// FromMetadata parses the Environment from a `metadata/endpoints` document - which is either a single
+// object (as returned by Azure Stack) or a list of clouds (as returned by Azure), in which case the
+// cloud using the specified Resource Manager endpoint (or failing that, with the specified name) is used
+func FromMetadata(data []byte, resourceManagerEndpoint, name string) (*Environment, error) {
+	clouds := make([]metadataCloud, 0)
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &clouds); err != nil {
+			return nil, fmt.Errorf("unmarshalling metadata: %+v", err)
+		}
+	} else {
+		var cloud metadataCloud
+		if err := json.Unmarshal(data, &cloud); err != nil {
+			return nil, fmt.Errorf("unmarshalling metadata: %+v", err)
+		}
+		clouds = append(clouds, cloud)
+	}
+
+	var match *metadataCloud
+	for i, cloud := range clouds {
+		if len(clouds) == 1 || normalizeEndpoint(cloud.ResourceManager) == normalizeEndpoint(resourceManagerEndpoint) {
+			match = &clouds[i]
+			break
+		}
+	}
+	if match == nil {
+		for i, cloud := range clouds {
+			if strings.EqualFold(cloud.Name, name) {
+				match = &clouds[i]
+				break
+			}
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no cloud was found in the metadata for the endpoint %q or named %q", resourceManagerEndpoint, name)
+	}
+
+	return match.toEnvironment(resourceManagerEndpoint, name)
+}
+
+type metadataCloud struct {
+	Name            string `json:"name"`
+	ResourceManager string `json:"resourceManager"`
+	Authentication  struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+	Suffixes struct {
+		AcrLoginServer    string `json:"acrLoginServer"`
+		KeyVaultDns       string `json:"keyVaultDns"`
+		SqlServerHostname string `json:"sqlServerHostname"`
+		Storage           string `json:"storage"`
+	} `json:"suffixes"`
+}
+
+func (c metadataCloud) toEnvironment(resourceManagerEndpoint, name string) (*Environment, error) {
+	if c.Authentication.LoginEndpoint == "" {
+		return nil, fmt.Errorf("the metadata did not contain a `loginEndpoint`")
+	}
+	if len(c.Authentication.Audiences) == 0 {
+		return nil, fmt.Errorf("the metadata did not contain any `audiences`")
+	}
+
+	// Azure Stack doesn't return it's own name/endpoint, since it's implied
+	if c.Name != "" {
+		name = c.Name
+	}
+	if c.ResourceManager != "" {
+		resourceManagerEndpoint = c.ResourceManager
+	}
+
+	return &Environment{
+		Name:                    name,
+		ActiveDirectoryEndpoint: strings.TrimSuffix(c.Authentication.LoginEndpoint, "/"),
+		ResourceManagerEndpoint: strings.TrimSuffix(resourceManagerEndpoint, "/"),
+		ResourceManagerAudience: c.Authentication.Audiences[0],
+		Suffixes: Suffixes{
+			ContainerRegistry: strings.TrimPrefix(c.Suffixes.AcrLoginServer, "."),
+			KeyVault:          strings.TrimPrefix(c.Suffixes.KeyVaultDns, "."),
+			SqlServer:         strings.TrimPrefix(c.Suffixes.SqlServerHostname, "."),
+			Storage:           strings.TrimPrefix(c.Suffixes.Storage, "."),
+		},
+	}, nil
+}
+
+// normalizeEndpoint returns the endpoint in a form which can be compared
+func normalizeEndpoint(input string) string {
+	return strings.ToLower(strings.TrimSuffix(input, "/"))
+}
@@ -0,0 +1,110 @@
+package environments
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromName(t *testing.T) {
+	testData := map[string]string{
+		"public":            Public.ResourceManagerEndpoint,
+		"AzureCloud":        Public.ResourceManagerEndpoint,
+		"china":             China.ResourceManagerEndpoint,
+		"AzureUSGovernment": "https://management.usgovcloudapi.net",
+	}
+	for name, expected := range testData {
+		env, err := FromName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if env.ResourceManagerEndpoint != expected {
+			t.Fatalf("expected the endpoint for %q to be %q but got %q", name, expected, env.ResourceManagerEndpoint)
+		}
+	}
+
+	if _, err := FromName("AzureGermanCloud"); err == nil {
+		t.Fatal("expected an error for an unknown environment but didn't get one")
+	}
+}
+
+func TestFromMetadataList(t *testing.T) {
+	data := []byte(`[
+  {
+    "name": "AzureCloud",
+    "resourceManager": "https://management.azure.com/",
+    "authentication": {
+      "loginEndpoint": "https://login.microsoftonline.com/",
+      "audiences": ["https://management.core.windows.net/", "https://management.azure.com/"]
+    },
+    "suffixes": {
+      "acrLoginServer": "azurecr.io",
+      "keyVaultDns": "vault.azure.net",
+      "sqlServerHostname": "database.windows.net",
+      "storage": "core.windows.net"
+    }
+  },
+  {
+    "name": "AzureChinaCloud",
+    "resourceManager": "https://management.chinacloudapi.cn/",
+    "authentication": {
+      "loginEndpoint": "https://login.chinacloudapi.cn/",
+      "audiences": ["https://management.core.chinacloudapi.cn/"]
+    },
+    "suffixes": {
+      "keyVaultDns": "vault.azure.cn",
+      "storage": "core.chinacloudapi.cn"
+    }
+  }
+]`)
+	env, err := FromMetadata(data, "https://management.chinacloudapi.cn", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env.Name != "AzureChinaCloud" {
+		t.Fatalf("expected the name to be %q but got %q", "AzureChinaCloud", env.Name)
+	}
+	if env.ActiveDirectoryEndpoint != "https://login.chinacloudapi.cn" {
+		t.Fatalf("unexpected Active Directory Endpoint %q", env.ActiveDirectoryEndpoint)
+	}
+	if env.Suffixes.KeyVault != "vault.azure.cn" {
+		t.Fatalf("unexpected Key Vault suffix %q", env.Suffixes.KeyVault)
+	}
+}
+
+func TestFromEndpointAzureStack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/endpoints" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+  "galleryEndpoint": "https://providers.local.azurestack.external:30016/",
+  "graphEndpoint": "https://graph.windows.net/",
+  "portalEndpoint": "https://portal.local.azurestack.external/",
+  "authentication": {
+    "loginEndpoint": "https://login.microsoftonline.com/",
+    "audiences": ["https://management.contoso.onmicrosoft.com/4de154de-f8a8-4017-af41-df619da68155"]
+  }
+}`))
+	}))
+	defer server.Close()
+
+	env, err := FromEndpoint(context.TODO(), server.URL, "AzureStack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env.Name != "AzureStack" {
+		t.Fatalf("expected the name to be %q but got %q", "AzureStack", env.Name)
+	}
+	if env.ResourceManagerEndpoint != server.URL {
+		t.Fatalf("expected the Resource Manager Endpoint to be %q but got %q", server.URL, env.ResourceManagerEndpoint)
+	}
+	if env.ResourceManagerAudience != "https://management.contoso.onmicrosoft.com/4de154de-f8a8-4017-af41-df619da68155" {
+		t.Fatalf("unexpected audience %q", env.ResourceManagerAudience)
+	}
+}
@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// ResponseError is returned when Resource Manager returns a Status Code which wasn't expected,
+// and contains the details of the error returned in the response body
+type ResponseError struct {
+	// StatusCode is the HTTP Status Code returned by Resource Manager
+	StatusCode int
+
+	// Code is the Resource Manager error code, e.g. `ResourceGroupNotFound`
+	Code string
+
+	// Message is the human-readable description of the error
+	Message string
+
+	// Target is the target of the error, when specified
+	Target string
+
+	Details        []ResponseErrorDetail
+	AdditionalInfo []ResponseErrorAdditionalInfo
+
+	// RequestId is the value of the `x-ms-request-id` header
+	RequestId string
+
+	// CorrelationId is the value of the `x-ms-correlation-request-id` header
+	CorrelationId string
+
+	// HttpResponse is the raw response, the body of which can be read again
+	HttpResponse *http.Response
+}
+
+type ResponseErrorDetail struct {
+	Code           string                        `json:"code"`
+	Message        string                        `json:"message"`
+	Target         string                        `json:"target,omitempty"`
+	Details        []ResponseErrorDetail         `json:"details,omitempty"`
+	AdditionalInfo []ResponseErrorAdditionalInfo `json:"additionalInfo,omitempty"`
+}
+
+type ResponseErrorAdditionalInfo struct {
+	Type string      `json:"type"`
+	Info interface{} `json:"info"`
+}
+
+func (e ResponseError) Error() string {
+	status := http.StatusText(e.StatusCode)
+	if e.Code == "" {
+		return fmt.Sprintf("unexpected status %d (%s)", e.StatusCode, status)
+	}
+
+	message := fmt.Sprintf("unexpected status %d (%s) with error %s: %s", e.StatusCode, status, e.Code, e.Message)
+	if e.RequestId != "" {
+		message = fmt.Sprintf("%s (request id %q)", message, e.RequestId)
+	}
+	return message
+}
+
+// newResponseError parses the Resource Manager error from the body of the response, which is
+// buffered so that the response body can still be read by the caller
+func newResponseError(resp *http.Response) ResponseError {
+	result := ResponseError{
+		StatusCode:    resp.StatusCode,
+		RequestId:     resp.Header.Get("x-ms-request-id"),
+		CorrelationId: resp.Header.Get("x-ms-correlation-request-id"),
+		HttpResponse:  resp,
+	}
+
+	if resp.Body == nil {
+		return result
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) == 0 {
+		return result
+	}
+
+	// most Resource Providers wrap the error in an `error` object, however some don't
+	var out struct {
+		Error *ResponseErrorDetail `json:"error"`
+		ResponseErrorDetail
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return result
+	}
+	detail := out.ResponseErrorDetail
+	if out.Error != nil {
+		detail = *out.Error
+	}
+
+	result.Code = detail.Code
+	result.Message = detail.Message
+	result.Target = detail.Target
+	result.Details = detail.Details
+	result.AdditionalInfo = detail.AdditionalInfo
+	return result
+}
+
+// LongRunningOperationError is returned when polling a Long Running Operation which completes in a terminal
+// failure state, such as `Failed` or `Canceled`
+type LongRunningOperationError struct {
+	// Status is the terminal status of the operation, e.g. `Failed` or `Canceled`
+	Status string
+
+	// Detail contains the error returned for the operation, when specified
+	Detail *ResponseErrorDetail
+
+	// HttpResponse is the raw response for the final poll, the body of which can be read again
+	HttpResponse *http.Response
+}
+
+func (e LongRunningOperationError) Error() string {
+	if e.Detail == nil || e.Detail.Code == "" {
+		return fmt.Sprintf("the long running operation completed with the status %q", e.Status)
+	}
+
+	return fmt.Sprintf("the long running operation completed with the status %q and error %s: %s", e.Status, e.Detail.Code, e.Detail.Message)
+}
+
+// IsNotFound returns whether the error is a ResponseError with the Status Code 404 (Not Found)
+func IsNotFound(err error) bool {
+	return isResponseErrorWithStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict returns whether the error is a ResponseError with the Status Code 409 (Conflict)
+func IsConflict(err error) bool {
+	return isResponseErrorWithStatusCode(err, http.StatusConflict)
+}
+
+// IsForbidden returns whether the error is a ResponseError with the Status Code 403 (Forbidden)
+func IsForbidden(err error) bool {
+	return isResponseErrorWithStatusCode(err, http.StatusForbidden)
+}
+
+// IsPreconditionFailed returns whether the error is a ResponseError with the Status Code 412 (Precondition Failed),
+// which is returned when the `If-Match` or `If-None-Match` condition for a request isn't met - for example
+// when the resource has been modified since it was retrieved
+func IsPreconditionFailed(err error) bool {
+	return isResponseErrorWithStatusCode(err, http.StatusPreconditionFailed)
+}
+
+func isResponseErrorWithStatusCode(err error, statusCode int) bool {
+	var responseErr ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode == statusCode
+	}
+
+	return false
+}
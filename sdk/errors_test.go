@@ -0,0 +1,146 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+func TestResponseErrorParsesResourceManagerError(t *testing.T) {
+	body := `{
+  "error": {
+    "code": "InvalidTemplateDeployment",
+    "message": "The template deployment failed.",
+    "details": [
+      {
+        "code": "QuotaExceeded",
+        "message": "Operation could not be completed as it results in exceeding approved quota.",
+        "target": "Microsoft.Compute/virtualMachines"
+      }
+    ],
+    "additionalInfo": [
+      {
+        "type": "PolicyViolation",
+        "info": {
+          "policyDefinitionName": "allowed-locations"
+        }
+      }
+    ]
+  }
+}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ms-request-id", "some-request")
+		w.Header().Set("x-ms-correlation-request-id", "some-correlation")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	_, err := client.Get(context.TODO(), input)
+	// errors should remain inspectable when wrapped by the generated clients
+	err = fmt.Errorf("sending Request: %w", err)
+
+	var responseErr ResponseError
+	if !errors.As(err, &responseErr) {
+		t.Fatalf("expected a ResponseError but got %+v", err)
+	}
+	if responseErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected the status code to be %d but got %d", http.StatusBadRequest, responseErr.StatusCode)
+	}
+	if responseErr.Code != "InvalidTemplateDeployment" {
+		t.Fatalf("expected the code to be %q but got %q", "InvalidTemplateDeployment", responseErr.Code)
+	}
+	if len(responseErr.Details) != 1 || responseErr.Details[0].Code != "QuotaExceeded" {
+		t.Fatalf("expected a single `QuotaExceeded` detail but got %+v", responseErr.Details)
+	}
+	if len(responseErr.AdditionalInfo) != 1 || responseErr.AdditionalInfo[0].Type != "PolicyViolation" {
+		t.Fatalf("expected a single `PolicyViolation` additional info but got %+v", responseErr.AdditionalInfo)
+	}
+	if responseErr.RequestId != "some-request" || responseErr.CorrelationId != "some-correlation" {
+		t.Fatalf("expected the request and correlation ids to be set but got %q and %q", responseErr.RequestId, responseErr.CorrelationId)
+	}
+
+	raw, err := ioutil.ReadAll(responseErr.HttpResponse.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != body {
+		t.Fatalf("expected the raw body to still be readable")
+	}
+}
+
+func TestResponseErrorHelpers(t *testing.T) {
+	testData := []struct {
+		statusCode         int
+		body               string
+		notFound           bool
+		conflict           bool
+		preconditionFailed bool
+	}{
+		{
+			statusCode: http.StatusNotFound,
+			body:       `{"error":{"code":"ResourceGroupNotFound","message":"Resource group 'example' could not be found."}}`,
+			notFound:   true,
+		},
+		{
+			statusCode: http.StatusConflict,
+			// some Resource Providers don't wrap the error
+			body:     `{"code":"Conflict","message":"The resource is being modified."}`,
+			conflict: true,
+		},
+		{
+			statusCode:         http.StatusPreconditionFailed,
+			body:               `{"error":{"code":"PreconditionFailed","message":"The specified precondition 'If-Match' failed."}}`,
+			preconditionFailed: true,
+		},
+		{
+			statusCode: http.StatusForbidden,
+			body:       `{"error":{"code":"AuthorizationFailed","message":"The client does not have authorization."}}`,
+		},
+	}
+	for _, v := range testData {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(v.statusCode)
+			w.Write([]byte(v.body))
+		}))
+
+		client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+		input := GetHttpRequestInput{
+			ExpectedStatusCodes: []int{http.StatusOK},
+			Uri:                 server.URL,
+		}
+		_, err := client.Get(context.TODO(), input)
+		server.Close()
+
+		if IsNotFound(err) != v.notFound {
+			t.Fatalf("expected IsNotFound to be %t for %d", v.notFound, v.statusCode)
+		}
+		if IsConflict(err) != v.conflict {
+			t.Fatalf("expected IsConflict to be %t for %d", v.conflict, v.statusCode)
+		}
+		if IsPreconditionFailed(err) != v.preconditionFailed {
+			t.Fatalf("expected IsPreconditionFailed to be %t for %d", v.preconditionFailed, v.statusCode)
+		}
+
+		var responseErr ResponseError
+		if !errors.As(err, &responseErr) || responseErr.Code == "" {
+			t.Fatalf("expected the error code to be parsed for %d but got %+v", v.statusCode, err)
+		}
+	}
+
+	if IsNotFound(fmt.Errorf("not found")) {
+		t.Fatal("expected IsNotFound to be false for an error which isn't a ResponseError")
+	}
+}
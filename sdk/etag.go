@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ETagAny matches any version of a resource - when used as the `If-Match` value the request only succeeds
+// if the resource exists, and when used as the `If-None-Match` value only if the resource doesn't exist
+const ETagAny = "*"
+
+// ETagFromResponse returns the version of the resource from the response - this should be sent as-is in the
+// `If-Match` header to ensure the resource hasn't been modified since it was retrieved.
+//
+// This is the `ETag` header when present, falling back to the `etag` field in the response body, which is
+// where most Resource Providers return it. Resources which support neither (such as Resource Groups) don't
+// support conditional requests, in which case nil is returned.
+func ETagFromResponse(resp *http.Response) *string {
+	if resp == nil {
+		return nil
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return &etag
+	}
+
+	if resp.Body == nil {
+		return nil
+	}
+	body, err := readResponseBody(resp)
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+
+	var out struct {
+		ETag string `json:"etag"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil || out.ETag == "" {
+		return nil
+	}
+
+	return &out.ETag
+}
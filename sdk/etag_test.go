@@ -0,0 +1,113 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+func TestConditionalRequestUsingETag(t *testing.T) {
+	var lock sync.Mutex
+	currentETag := `W/"2"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", currentETag)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if r.Header.Get("If-Match") != currentETag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`{"error":{"code":"PreconditionFailed","message":"The specified precondition 'If-Match' failed."}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	resp, err := client.Get(context.TODO(), GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := ETagFromResponse(resp)
+	if etag == nil || *etag != `W/"2"` {
+		t.Fatalf("expected the ETag to be %q but got %v", `W/"2"`, etag)
+	}
+
+	input := PatchHttpRequestInput{
+		Body:                map[string]string{},
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Headers: map[string]string{
+			"If-Match": *etag,
+		},
+		Uri: server.URL,
+	}
+	if _, err := client.PatchJson(context.TODO(), input); err != nil {
+		t.Fatalf("expected the update with the current ETag to succeed but got %+v", err)
+	}
+
+	// another client has since updated the resource
+	lock.Lock()
+	currentETag = `W/"3"`
+	lock.Unlock()
+	_, err = client.PatchJson(context.TODO(), input)
+	if !IsPreconditionFailed(err) {
+		t.Fatalf("expected a Precondition Failed error when using an outdated ETag but got %+v", err)
+	}
+}
+
+func TestETagFromResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/without-etag" {
+			w.Write([]byte(`{"name":"example"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"example","etag":"W/\"4\""}`))
+	}))
+	defer server.Close()
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	testData := map[string]string{
+		"/with-etag":    `W/"4"`,
+		"/without-etag": "",
+	}
+	for path, expected := range testData {
+		var out struct {
+			Name string `json:"name"`
+		}
+		resp, err := client.GetJson(context.TODO(), GetHttpRequestInput{
+			ExpectedStatusCodes: []int{http.StatusOK},
+			Uri:                 server.URL + path,
+		}, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Name != "example" {
+			t.Fatalf("expected the response to be unmarshalled for %q but got %+v", path, out)
+		}
+
+		actual := ETagFromResponse(resp)
+		if expected == "" {
+			if actual != nil {
+				t.Fatalf("expected no ETag for %q but got %q", path, *actual)
+			}
+			continue
+		}
+		if actual == nil || *actual != expected {
+			t.Fatalf("expected the ETag for %q to be %q but got %v", path, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubscriptionID is the ID of the Subscription which the client is configured for
+type SubscriptionID struct{}
+
+func NewSubscriptionID() SubscriptionID {
+	return SubscriptionID{}
+}
+
+func (id SubscriptionID) ID(subscriptionId string) string {
+	return fmt.Sprintf("/subscriptions/%s", subscriptionId)
+}
+
+// ResourceGroupID is the ID of a Resource Group within the Subscription which the client is configured for
+type ResourceGroupID struct {
+	Name string
+}
+
+func NewResourceGroupID(name string) ResourceGroupID {
+	return ResourceGroupID{
+		Name: name,
+	}
+}
+
+func (id ResourceGroupID) ID(subscriptionId string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionId, id.Name)
+}
+
+// ScopeID is an arbitrary parent scope, such as a Management Group, Subscription, Resource Group or Resource,
+// which is used to list or manage extension resources (e.g. Locks or Role Assignments) within that scope
+type ScopeID struct {
+	Scope string
+}
+
+func NewScopeID(scope string) ScopeID {
+	return ScopeID{
+		Scope: scope,
+	}
+}
+
+func (id ScopeID) ID() string {
+	if scope := NormalizeScope(id.Scope); scope != "" {
+		return scope
+	}
+
+	// the tenant-root scope
+	return "/"
+}
+
+// NormalizeScope returns the scope with a leading slash and without a trailing slash, so that it can be prefixed
+// to the remainder of a Resource ID - which for the tenant-root scope `/` is an empty string
+func NormalizeScope(scope string) string {
+	trimmed := strings.Trim(scope, "/")
+	if trimmed == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("/%s", trimmed)
+}
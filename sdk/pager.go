@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type ListHttpRequestInput struct {
+	ExpectedStatusCodes []int
+
+	// Filter is sent as the `$filter` query parameter for the first page, when specified
+	Filter *string
+
+	// Headers are additional headers to send with the request for each page
+	Headers map[string]string
+
+	// QueryParameters are additional query parameters to send with the request for the first page,
+	// subsequent pages use the `nextLink` returned by Resource Manager which already includes these
+	QueryParameters map[string]string
+
+	// SkipToken is sent as the `$skipToken` query parameter for the first page, when specified
+	SkipToken *string
+
+	// Top is sent as the `$top` query parameter for the first page, when specified
+	Top *int
+
+	Uri string
+}
+
+// Page is a single page of results from a list operation
+type Page[T any] struct {
+	HttpResponse *http.Response
+	NextLink     *string
+	Values       []T
+}
+
+// Pager retrieves the results of a list operation page-by-page, following the `nextLink` returned by Resource Manager
+type Pager[T any] struct {
+	baseClient BaseClient
+	input      ListHttpRequestInput
+
+	// nextLink is the URI of the next page, which is nil once the last page has been retrieved
+	nextLink *string
+	started  bool
+}
+
+func NewPager[T any](baseClient BaseClient, input ListHttpRequestInput) *Pager[T] {
+	return &Pager[T]{
+		baseClient: baseClient,
+		input:      input,
+	}
+}
+
+// More returns whether there are more pages to retrieve
+func (p *Pager[T]) More() bool {
+	return !p.started || p.nextLink != nil
+}
+
+// NextPage retrieves the next page of results
+func (p *Pager[T]) NextPage(ctx context.Context) (*Page[T], error) {
+	if !p.More() {
+		return nil, fmt.Errorf("there are no more pages to retrieve")
+	}
+
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: p.input.ExpectedStatusCodes,
+		Headers:             p.input.Headers,
+	}
+	if p.started {
+		input.Uri = *p.nextLink
+	} else {
+		input.QueryParameters = p.firstPageQueryParameters()
+		input.Uri = p.input.Uri
+	}
+
+	var out struct {
+		NextLink *string `json:"nextLink"`
+		Value    []T     `json:"value"`
+	}
+	resp, err := p.baseClient.GetJson(ctx, input, &out)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving page: %w", err)
+	}
+
+	p.started = true
+	p.nextLink = nil
+	if out.NextLink != nil && *out.NextLink != "" {
+		p.nextLink = out.NextLink
+	}
+
+	return &Page[T]{
+		HttpResponse: resp,
+		NextLink:     p.nextLink,
+		Values:       out.Value,
+	}, nil
+}
+
+// All retrieves all of the remaining pages, returning the combined results
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	results := make([]T, 0)
+	for p.More() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, page.Values...)
+	}
+
+	return results, nil
+}
+
+func (p *Pager[T]) firstPageQueryParameters() map[string]string {
+	output := make(map[string]string)
+	for k, v := range p.input.QueryParameters {
+		output[k] = v
+	}
+
+	if p.input.Filter != nil {
+		output["$filter"] = *p.input.Filter
+	}
+	if p.input.SkipToken != nil {
+		output["$skipToken"] = *p.input.SkipToken
+	}
+	if p.input.Top != nil {
+		output["$top"] = strconv.Itoa(*p.input.Top)
+	}
+
+	return output
+}
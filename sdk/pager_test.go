@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+type pagerTestModel struct {
+	Name string `json:"name"`
+}
+
+func TestPagerFollowsNextLink(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			if actual := r.URL.Query().Get("$filter"); actual != "tagName eq 'env'" {
+				t.Errorf("expected the `$filter` to be %q but got %q", "tagName eq 'env'", actual)
+			}
+			if actual := r.URL.Query().Get("$top"); actual != "2" {
+				t.Errorf("expected `$top` to be %q but got %q", "2", actual)
+			}
+			if actual := r.URL.Query().Get("api-version"); actual != "2018-05-01" {
+				t.Errorf("expected the `api-version` to be %q but got %q", "2018-05-01", actual)
+			}
+			w.Write([]byte(fmt.Sprintf(`{"value":[{"name":"first"},{"name":"second"}],"nextLink":"%s/list?page=2"}`, server.URL)))
+
+		case "2":
+			w.Write([]byte(fmt.Sprintf(`{"value":[{"name":"third"}],"nextLink":"%s/list?page=3"}`, server.URL)))
+
+		case "3":
+			w.Write([]byte(`{"value":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	filter := "tagName eq 'env'"
+	top := 2
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	input := ListHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Filter:              &filter,
+		Top:                 &top,
+		Uri:                 server.URL + "/list?api-version=2018-05-01",
+	}
+
+	pager := NewPager[pagerTestModel](client, input)
+	page, err := pager.NextPage(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Values) != 2 || page.NextLink == nil {
+		t.Fatalf("expected the first page to contain 2 values and a next link but got %+v", page)
+	}
+
+	remaining, err := pager.All(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 1 || remaining[0].Name != "third" {
+		t.Fatalf("expected the remaining pages to contain `third` but got %+v", remaining)
+	}
+	if pager.More() {
+		t.Fatal("expected there to be no more pages")
+	}
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+)
+
+// Policy is a stage in a Pipeline, which can inspect or modify the request before calling next
+// to pass it to the next stage, and then inspect or modify the response
+type Policy interface {
+	Do(req *http.Request, next NextPolicy) (*http.Response, error)
+}
+
+// NextPolicy sends the request to the next stage of the Pipeline
+type NextPolicy func(req *http.Request) (*http.Response, error)
+
+// PolicyFunc allows a function to be used as a Policy
+type PolicyFunc func(req *http.Request, next NextPolicy) (*http.Response, error)
+
+func (f PolicyFunc) Do(req *http.Request, next NextPolicy) (*http.Response, error) {
+	return f(req, next)
+}
+
+// Pipeline sends a request through an ordered list of Policies before sending it using the HTTP Client
+type Pipeline struct {
+	httpClient *http.Client
+	policies   []Policy
+}
+
+func NewPipeline(httpClient *http.Client, policies ...Policy) Pipeline {
+	return Pipeline{
+		httpClient: httpClient,
+		policies:   policies,
+	}
+}
+
+func (p Pipeline) Do(req *http.Request) (*http.Response, error) {
+	return p.next(0)(req)
+}
+
+func (p Pipeline) next(index int) NextPolicy {
+	if index >= len(p.policies) {
+		return p.httpClient.Do
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		return p.policies[index].Do(req, p.next(index+1))
+	}
+}
+
+type expectedStatusCodesKey struct{}
+
+func withExpectedStatusCodes(ctx context.Context, expectedStatusCodes []int) context.Context {
+	return context.WithValue(ctx, expectedStatusCodesKey{}, expectedStatusCodes)
+}
+
+// expectedStatusCodesFromContext returns the Status Codes the caller of the Pipeline is expecting
+func expectedStatusCodesFromContext(ctx context.Context) []int {
+	if v, ok := ctx.Value(expectedStatusCodesKey{}).([]int); ok {
+		return v
+	}
+
+	return nil
+}
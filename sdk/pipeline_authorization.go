@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// authorizationPolicy adds the `Authorization` (and `x-ms-authorization-auxiliary`) headers to each attempt
+type authorizationPolicy struct {
+	authorizer         Authorizer
+	auxiliaryTenantIds []string
+	tokenAudience      string
+}
+
+func (p authorizationPolicy) Do(req *http.Request, next NextPolicy) (*http.Response, error) {
+	ctx := req.Context()
+
+	// tokens are retrieved for each attempt, since they can expire whilst waiting to retry
+	token, err := p.authorizer.Token(ctx, p.tokenAudience)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving auth token: %+v", err)
+	}
+	req.Header.Set("Authorization", token.AuthorizationHeader())
+
+	if len(p.auxiliaryTenantIds) > 0 {
+		auxiliaryTokens, err := p.authorizer.AuxiliaryTokens(ctx, p.tokenAudience, p.auxiliaryTenantIds)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving auxiliary auth tokens: %+v", err)
+		}
+
+		headers := make([]string, 0)
+		for _, auxiliaryToken := range auxiliaryTokens {
+			headers = append(headers, auxiliaryToken.AuthorizationHeader())
+		}
+		req.Header.Set("x-ms-authorization-auxiliary", strings.Join(headers, ", "))
+	}
+
+	return next(req)
+}
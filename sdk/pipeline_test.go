@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+func TestPipelinePolicyOrdering(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if actual := r.Header.Get("X-Custom"); actual != "hello" {
+			t.Errorf("expected the `X-Custom` header to be %q but got %q", "hello", actual)
+		}
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	calls := make([]string, 0)
+	recordingPolicy := func(name string) Policy {
+		return PolicyFunc(func(req *http.Request, next NextPolicy) (*http.Response, error) {
+			calls = append(calls, name)
+			return next(req)
+		})
+	}
+	headerPolicy := PolicyFunc(func(req *http.Request, next NextPolicy) (*http.Response, error) {
+		req.Header.Set("X-Custom", "hello")
+		return next(req)
+	})
+
+	transportCalls := 0
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			transportCalls++
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{
+		HttpClient:       httpClient,
+		PerCallPolicies:  []Policy{recordingPolicy("per-call"), headerPolicy},
+		PerRetryPolicies: []Policy{recordingPolicy("per-retry")},
+	})
+	client.clock = &fakeClock{}
+
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	if _, err := client.Get(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"per-call", "per-retry", "per-retry"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected the policies to be called %+v but got %+v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("expected the policies to be called %+v but got %+v", expected, calls)
+		}
+	}
+
+	if transportCalls != 2 {
+		t.Fatalf("expected the custom HTTP Client to be used twice but got %d", transportCalls)
+	}
+}
+
+func TestPipelineWithoutAuthorizer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actual := r.Header.Get("Authorization"); actual != "Custom abc123" {
+			t.Errorf("expected the `Authorization` header to be %q but got %q", "Custom abc123", actual)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the request is authorized by a custom policy rather than an Authorizer
+	authorizationPolicy := PolicyFunc(func(req *http.Request, next NextPolicy) (*http.Response, error) {
+		req.Header.Set("Authorization", "Custom abc123")
+		return next(req)
+	})
+	client := NewBaseClient(environments.Public, nil, ClientOptions{
+		PerRetryPolicies: []Policy{authorizationPolicy},
+	})
+
+	input := GetHttpRequestInput{
+		ExpectedStatusCodes: []int{http.StatusOK},
+		Uri:                 server.URL,
+	}
+	if _, err := client.Get(context.TODO(), input); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
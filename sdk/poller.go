@@ -2,19 +2,26 @@ package sdk
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type Poller interface {
 	GetOriginalResponse() *http.Response
 	GetLatestPollResponse() *http.Response
 	PollUntilDone(ctx context.Context) error
+
+	// ResumeToken returns a token which can be used to resume polling later (e.g. in another process)
+	// using ResumePoller
+	ResumeToken() (string, error)
 }
 
 func DeterminePoller(response *http.Response, baseClient *BaseClient, uri string) (Poller, error) {
 	// we could clearly make this smarter, but this is fine for now
-	poller, err := newLongRunningOperationPoller(response, baseClient)
+	poller, err := newLongRunningOperationPoller(response, baseClient, uri)
 	if err == nil {
 		return poller, nil
 	}
@@ -26,3 +33,101 @@ func DeterminePoller(response *http.Response, baseClient *BaseClient, uri string
 
 	return nil, fmt.Errorf("unable to determine poller type")
 }
+
+// requestMethod returns the HTTP Method of the request which returned the response, when known
+func requestMethod(response *http.Response) string {
+	if response == nil || response.Request == nil {
+		return ""
+	}
+
+	return response.Request.Method
+}
+
+// pollingDeadline returns the time by which polling must complete, when a Polling Timeout is configured
+func pollingDeadline(baseClient *BaseClient) *time.Time {
+	if baseClient.pollingTimeout <= 0 {
+		return nil
+	}
+
+	deadline := baseClient.clock.Now().Add(baseClient.pollingTimeout)
+	return &deadline
+}
+
+// waitForNextPoll waits for the specified interval before polling again - returning the context's error
+// as soon as it's cancelled. When a polling deadline is set, the wait is shortened so that a final poll happens
+// at the deadline, after which an error is returned since the deadline has passed
+func waitForNextPoll(ctx context.Context, clock clock, interval time.Duration, deadline *time.Time) error {
+	if deadline != nil {
+		remaining := deadline.Sub(clock.Now())
+		if remaining <= 0 {
+			return fmt.Errorf("timed out waiting for the operation to complete: %w", context.DeadlineExceeded)
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(interval):
+		return nil
+	}
+}
+
+// inProgressStatuses are the (non-terminal) statuses returned by Resource Providers whilst an operation is in progress
+var inProgressStatuses = []string{
+	"Accepted",
+	"Creating",
+	"Deleting",
+	"InProgress",
+	"NotStarted",
+	"Pending",
+	"Provisioning",
+	"Running",
+	"Updating",
+}
+
+// operationCompleted returns whether the operation has completed based on its status, returning a
+// LongRunningOperationError if it completed in a terminal failure state. An empty status is treated
+// as completed, since there's nothing further to wait for.
+//
+// Any status other than `Succeeded` and the known in-progress statuses is treated as a terminal failure state,
+// since Resource Providers can return terminal states other than `Failed` and `Canceled` (e.g. `Deleted`)
+// which would otherwise be polled forever
+func operationCompleted(status string, detail *ResponseErrorDetail, resp *http.Response) (bool, error) {
+	if status == "" || strings.EqualFold(status, "Succeeded") {
+		return true, nil
+	}
+
+	for _, inProgress := range inProgressStatuses {
+		if strings.EqualFold(status, inProgress) {
+			return false, nil
+		}
+	}
+
+	// e.g. `Failed`, `Canceled` or the British spelling `Cancelled` used by some Resource Providers
+	return true, LongRunningOperationError{
+		Status:       status,
+		Detail:       detail,
+		HttpResponse: resp,
+	}
+}
+
+// decodePollResponse unmarshals the body of the poll response into `out`, buffering the body
+// so that it can be read again by the caller
+func decodePollResponse(resp *http.Response, out interface{}) error {
+	if resp.Body == nil {
+		return nil
+	}
+
+	body, err := readResponseBody(resp)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, out)
+}
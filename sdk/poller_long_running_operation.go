@@ -4,16 +4,28 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 )
 
 type LongRunningOperationPoller struct {
+	// asyncOperation specifies whether the poll location is from the `Azure-AsyncOperation` header, in which case
+	// the status of the operation is returned in the response body, rather than the `Location` header
+	asyncOperation bool
+
 	baseClient         *BaseClient
 	latestPollResponse *http.Response
 	originalResponse   *http.Response
 	pollInterval       time.Duration
 	pollLocation       string
+
+	// location is the `Location` header returned in the original response, which (depending on the operation)
+	// is where the final result of the operation is retrieved from
+	location string
+
+	// method and originalUri are the HTTP Method and URI of the request which started the operation
+	method      string
+	originalUri string
 }
 
 func (p LongRunningOperationPoller) GetLatestPollResponse() *http.Response {
@@ -24,7 +36,7 @@ func (p LongRunningOperationPoller) GetOriginalResponse() *http.Response {
 	return p.originalResponse
 }
 
-func newLongRunningOperationPoller(response *http.Response, baseClient *BaseClient) (Poller, error) {
+func newLongRunningOperationPoller(response *http.Response, baseClient *BaseClient, uri string) (Poller, error) {
 	if response == nil {
 		return nil, fmt.Errorf("response cannot be nil")
 	}
@@ -33,63 +45,159 @@ func newLongRunningOperationPoller(response *http.Response, baseClient *BaseClie
 		return nil, fmt.Errorf("status code %d (%s) is not a long running operation", response.StatusCode, response.Status)
 	}
 
+	location := response.Header.Get("Location")
 	locationHeader := response.Header.Get("Azure-AsyncOperation")
+	asyncOperation := locationHeader != ""
 	if locationHeader == "" {
-		locationHeader = response.Header.Get("Location")
+		locationHeader = location
 		if locationHeader == "" {
 			return nil, fmt.Errorf("the `Azure-AsyncOperation` and `Location` headers were empty")
 		}
 	}
 
-	retryAfter := 15
-	if retryAfterHeader := response.Header.Get("Retry-After"); retryAfterHeader != "" {
-		parsed, err := strconv.ParseInt(retryAfterHeader, 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("parsing %q as an int: %+v", retryAfterHeader, err)
-		}
-
-		retryAfter = int(parsed)
-	}
-
 	return &LongRunningOperationPoller{
+		asyncOperation:   asyncOperation,
 		baseClient:       baseClient,
+		location:         location,
+		method:           requestMethod(response),
 		originalResponse: response,
-		pollInterval:     time.Duration(retryAfter) * time.Second,
+		originalUri:      uri,
+		pollInterval:     baseClient.pollingPolicy.initialPollInterval(response, baseClient.clock.Now()),
 		pollLocation:     locationHeader,
 	}, nil
 }
 
+func (p LongRunningOperationPoller) ResumeToken() (string, error) {
+	return p.resumeToken().encode()
+}
+
+func (p LongRunningOperationPoller) resumeToken() resumeToken {
+	return resumeToken{
+		Type:           longRunningOperationPollerType,
+		AsyncOperation: p.asyncOperation,
+		Location:       p.location,
+		Method:         p.method,
+		OriginalUri:    p.originalUri,
+		PollInterval:   p.pollInterval,
+		PollLocation:   p.pollLocation,
+	}
+}
+
 func (p *LongRunningOperationPoller) PollUntilDone(ctx context.Context) error {
+	deadline := pollingDeadline(p.baseClient)
 	for {
 		// wait for the recommended amount of settings before continuing
-		time.Sleep(p.pollInterval)
+		if err := waitForNextPoll(ctx, p.baseClient.clock, p.pollInterval, deadline); err != nil {
+			return err
+		}
 
 		input := GetHttpRequestInput{
 			Uri: p.pollLocation,
 			ExpectedStatusCodes: []int{
-				http.StatusAccepted, // in progress
-				http.StatusOK,       // finished
+				http.StatusAccepted,  // in progress
+				http.StatusCreated,   // finished
+				http.StatusNoContent, // finished
+				http.StatusOK,        // finished, or the status is in the body
 			},
 		}
 
 		var err error
 		p.latestPollResponse, err = p.baseClient.Get(ctx, input)
 		if err != nil {
-			return fmt.Errorf("polling: %+v", err)
+			return fmt.Errorf("polling: %w", err)
+		}
+
+		// keep waiting
+		if p.latestPollResponse.StatusCode == http.StatusAccepted {
+			p.updatePollingState(p.latestPollResponse)
+			continue
 		}
 
-		// we should be done
-		if p.latestPollResponse.StatusCode == http.StatusOK {
+		// when polling the `Location` header any other status code means the operation has completed
+		// (unexpected status codes are returned as an error above)
+		if !p.asyncOperation {
 			return nil
 		}
 
-		// keep waiting
-		//if p.latestPollResponse.StatusCode == http.StatusAccepted {
-		//	// TODO: we could parse the location/retry-after header out, but it appears unnecessary
-		//}
+		// whereas the `Azure-AsyncOperation` URI returns the status of the operation in the body
+		var out asyncOperationResponse
+		if err := decodePollResponse(p.latestPollResponse, &out); err != nil {
+			return fmt.Errorf("decoding response: %+v", err)
+		}
+
+		completed, err := operationCompleted(out.Status, out.Error, p.latestPollResponse)
+		if err != nil {
+			return err
+		}
+		if completed {
+			return nil
+		}
 
-		continue
+		p.updatePollingState(p.latestPollResponse)
 	}
+}
+
+// updatePollingState updates the poll location and interval from the latest poll response, since
+// these can change whilst the operation is in progress
+func (p *LongRunningOperationPoller) updatePollingState(resp *http.Response) {
+	header := "Location"
+	if p.asyncOperation {
+		header = "Azure-AsyncOperation"
+	}
+	if location := resp.Header.Get(header); location != "" {
+		p.pollLocation = location
+	}
+
+	p.pollInterval = p.baseClient.pollingPolicy.nextPollInterval(resp, p.pollInterval, p.baseClient.clock.Now())
+}
+
+// finalResultUri returns the URI the final result of the operation should be retrieved from - which is nil
+// when the final result is the body of the latest poll response
+func (p LongRunningOperationPoller) finalResultUri(finalStateVia FinalStateVia) (*string, error) {
+	switch finalStateVia {
+	case FinalStateViaAzureAsyncOperation:
+		return nil, nil
+
+	case FinalStateViaLocation:
+		if p.location == "" {
+			return nil, fmt.Errorf("the final result is retrieved from the `Location` header but this was empty")
+		}
+		return p.locationResultUri(), nil
+
+	case FinalStateViaOriginalUri:
+		return p.originalResultUri()
+	}
+
+	// otherwise `PUT` and `PATCH` operations return the resource from the original URI, whereas other
+	// operations return the final result from the `Location` header (when present)
+	if strings.EqualFold(p.method, http.MethodPut) || strings.EqualFold(p.method, http.MethodPatch) {
+		return p.originalResultUri()
+	}
+	if p.location != "" {
+		return p.locationResultUri(), nil
+	}
+	return nil, nil
+}
+
+// locationResultUri returns the `Location` header, unless this was polled - in which case the latest
+// poll response contains the final result
+func (p LongRunningOperationPoller) locationResultUri() *string {
+	if !p.asyncOperation {
+		return nil
+	}
+
+	return &p.location
+}
+
+func (p LongRunningOperationPoller) originalResultUri() (*string, error) {
+	if p.originalUri == "" {
+		return nil, fmt.Errorf("the final result is retrieved from the original URI but this was empty")
+	}
+
+	return &p.originalUri, nil
+}
 
-	return nil
+type asyncOperationResponse struct {
+	Status string               `json:"status"`
+	Error  *ResponseErrorDetail `json:"error"`
 }
@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// PollingPolicy configures how frequently Long Running Operations are polled
+type PollingPolicy struct {
+	// InitialInterval is the interval between polls when the response doesn't contain a `Retry-After` header,
+	// which doubles for each subsequent poll which doesn't contain one
+	InitialInterval time.Duration
+
+	// MinInterval is the minimum interval between polls, which the `Retry-After` header is clamped to
+	MinInterval time.Duration
+
+	// MaxInterval is the maximum interval between polls, which the `Retry-After` header is clamped to
+	MaxInterval time.Duration
+}
+
+// DefaultPollingPolicy returns the PollingPolicy used when one isn't specified in the ClientOptions
+func DefaultPollingPolicy() PollingPolicy {
+	return PollingPolicy{
+		InitialInterval: 2 * time.Second,
+		MinInterval:     1 * time.Second,
+		MaxInterval:     60 * time.Second,
+	}
+}
+
+// initialPollInterval returns the interval before the first poll - using the `Retry-After` header from the
+// original response when present, otherwise the InitialInterval
+func (p PollingPolicy) initialPollInterval(resp *http.Response, now time.Time) time.Duration {
+	if resp != nil {
+		if interval, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return p.clamp(interval)
+		}
+	}
+
+	return p.clamp(p.InitialInterval)
+}
+
+// nextPollInterval returns the interval before the next poll - using the `Retry-After` header from the latest
+// poll response when present, otherwise backing off from the previous interval
+func (p PollingPolicy) nextPollInterval(resp *http.Response, previous time.Duration, now time.Time) time.Duration {
+	if resp != nil {
+		if interval, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return p.clamp(interval)
+		}
+	}
+
+	if previous <= 0 {
+		return p.clamp(p.InitialInterval)
+	}
+
+	return p.clamp(previous * 2)
+}
+
+// clamp returns the interval limited to the MinInterval and MaxInterval (when set)
+func (p PollingPolicy) clamp(interval time.Duration) time.Duration {
+	if p.MinInterval > 0 && interval < p.MinInterval {
+		return p.MinInterval
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		return p.MaxInterval
+	}
+
+	return interval
+}
@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPollingPolicyIntervals(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := PollingPolicy{
+		InitialInterval: 2 * time.Second,
+		MinInterval:     1 * time.Second,
+		MaxInterval:     60 * time.Second,
+	}
+	testData := []struct {
+		retryAfter string
+		previous   time.Duration
+		expected   time.Duration
+	}{
+		{
+			// no previous interval uses the initial interval
+			expected: 2 * time.Second,
+		},
+		{
+			// otherwise we back off from the previous interval
+			previous: 5 * time.Second,
+			expected: 10 * time.Second,
+		},
+		{
+			previous: 45 * time.Second,
+			expected: 60 * time.Second,
+		},
+		{
+			retryAfter: "7",
+			previous:   30 * time.Second,
+			expected:   7 * time.Second,
+		},
+		{
+			retryAfter: now.Add(20 * time.Second).Format(http.TimeFormat),
+			expected:   20 * time.Second,
+		},
+		{
+			retryAfter: "0",
+			expected:   1 * time.Second,
+		},
+		{
+			retryAfter: "3600",
+			expected:   60 * time.Second,
+		},
+		{
+			// an invalid value is ignored
+			retryAfter: "soon",
+			previous:   4 * time.Second,
+			expected:   8 * time.Second,
+		},
+	}
+	for _, v := range testData {
+		resp := &http.Response{
+			Header: http.Header{},
+		}
+		if v.retryAfter != "" {
+			resp.Header.Set("Retry-After", v.retryAfter)
+		}
+
+		actual := policy.nextPollInterval(resp, v.previous, now)
+		if actual != v.expected {
+			t.Fatalf("expected the interval for %q (previously %s) to be %s but got %s", v.retryAfter, v.previous, v.expected, actual)
+		}
+	}
+}
+
+func TestLongRunningOperationPollerHonoursPollResponses(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path == "/first" {
+			w.Header().Set("Location", "http://"+r.Host+"/second")
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	original := &http.Response{
+		StatusCode: http.StatusAccepted,
+		Header: http.Header{
+			"Location":    []string{server.URL + "/first"},
+			"Retry-After": []string{clock.now.Add(3 * time.Second).Format(http.TimeFormat)},
+		},
+	}
+	poller, err := newLongRunningOperationPoller(original, &client, "/original")
+	if err != nil {
+		t.Fatalf("building poller: %+v", err)
+	}
+	if err := poller.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 || requests[1] != "/second" {
+		t.Fatalf("expected the updated location to be polled but got %+v", requests)
+	}
+	if len(clock.delays) != 2 || clock.delays[1] != 5*time.Second {
+		t.Fatalf("expected the updated Retry-After to be used but got %+v", clock.delays)
+	}
+
+	// the HTTP-date is truncated to the second, so we only check this is within the expected range
+	if clock.delays[0] < 2*time.Second || clock.delays[0] > 3*time.Second {
+		t.Fatalf("expected the first delay to be around 3s but got %s", clock.delays[0])
+	}
+}
+
+func TestProvisioningStatePollerDoesntWaitForTheDefaultInterval(t *testing.T) {
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	poller, err := newProvisioningStatePoller(&http.Response{StatusCode: http.StatusCreated}, &client, "/original")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interval := poller.(*ProvisioningStatePoller).pollInterval
+	if interval != DefaultPollingPolicy().InitialInterval {
+		t.Fatalf("expected the first poll after %s but got %s", DefaultPollingPolicy().InitialInterval, interval)
+	}
+}
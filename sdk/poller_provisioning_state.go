@@ -2,10 +2,8 @@ package sdk
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -15,6 +13,9 @@ type ProvisioningStatePoller struct {
 	originalResponse   *http.Response
 	pollInterval       time.Duration
 	pollLocation       string
+
+	// method is the HTTP Method of the request which started the operation, the URI of which is polled
+	method string
 }
 
 func (p ProvisioningStatePoller) GetLatestPollResponse() *http.Response {
@@ -28,16 +29,34 @@ func (p ProvisioningStatePoller) GetOriginalResponse() *http.Response {
 func newProvisioningStatePoller(response *http.Response, baseClient *BaseClient, uri string) (Poller, error) {
 	return &ProvisioningStatePoller{
 		baseClient:       baseClient,
+		method:           requestMethod(response),
 		originalResponse: response,
-		pollInterval:     15 * time.Second,
+		pollInterval:     baseClient.pollingPolicy.initialPollInterval(response, baseClient.clock.Now()),
 		pollLocation:     uri,
 	}, nil
 }
 
+func (p ProvisioningStatePoller) ResumeToken() (string, error) {
+	return p.resumeToken().encode()
+}
+
+func (p ProvisioningStatePoller) resumeToken() resumeToken {
+	return resumeToken{
+		Type:         provisioningStatePollerType,
+		Method:       p.method,
+		OriginalUri:  p.pollLocation,
+		PollInterval: p.pollInterval,
+		PollLocation: p.pollLocation,
+	}
+}
+
 func (p *ProvisioningStatePoller) PollUntilDone(ctx context.Context) error {
+	deadline := pollingDeadline(p.baseClient)
 	for {
 		// wait for the recommended amount of settings before continuing
-		time.Sleep(p.pollInterval)
+		if err := waitForNextPoll(ctx, p.baseClient.clock, p.pollInterval, deadline); err != nil {
+			return err
+		}
 
 		input := GetHttpRequestInput{
 			Uri: p.pollLocation,
@@ -49,24 +68,30 @@ func (p *ProvisioningStatePoller) PollUntilDone(ctx context.Context) error {
 		var err error
 		p.latestPollResponse, err = p.baseClient.Get(ctx, input)
 		if err != nil {
-			return fmt.Errorf("polling: %+v", err)
+			return fmt.Errorf("polling: %w", err)
 		}
 
 		var out ProvisioningStateResponse
-		if err := json.NewDecoder(p.latestPollResponse.Body).Decode(&out); err != nil {
+		if err := decodePollResponse(p.latestPollResponse, &out); err != nil {
 			return fmt.Errorf("decoding response: %+v", err)
 		}
 
-		if strings.EqualFold(out.Properties.ProvisioningState, "Succeeded") {
+		completed, err := operationCompleted(out.Properties.ProvisioningState, nil, p.latestPollResponse)
+		if err != nil {
+			return err
+		}
+		if completed {
 			return nil
 		}
 
-		// TODO: handle failed
-
-		continue
+		p.pollInterval = p.baseClient.pollingPolicy.nextPollInterval(p.latestPollResponse, p.pollInterval, p.baseClient.clock.Now())
 	}
+}
 
-	return nil
+// finalResultUri returns nil, since the latest poll response is the resource itself and
+// as such contains the final result of the operation
+func (p ProvisioningStatePoller) finalResultUri(_ FinalStateVia) (*string, error) {
+	return nil, nil
 }
 
 type ProvisioningStateResponse struct {
@@ -74,5 +99,5 @@ type ProvisioningStateResponse struct {
 }
 
 type ProvisioningStateResponseProperties struct {
-	ProvisioningState string `json:"provisioningState`
+	ProvisioningState string `json:"provisioningState"`
 }
@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// FinalStateVia specifies where the final result of a Long Running Operation is retrieved from once
+// it's completed, matching the `final-state-via` option defined for the operation in the API Definition
+type FinalStateVia string
+
+const (
+	// FinalStateViaDefault retrieves the final result from the original URI for `PUT` and `PATCH` operations,
+	// otherwise from the `Location` header when this is returned
+	FinalStateViaDefault FinalStateVia = ""
+
+	// FinalStateViaAzureAsyncOperation retrieves the final result from the body of the final poll response
+	FinalStateViaAzureAsyncOperation FinalStateVia = "azure-async-operation"
+
+	// FinalStateViaLocation retrieves the final result from the `Location` header in the original response
+	FinalStateViaLocation FinalStateVia = "location"
+
+	// FinalStateViaOriginalUri retrieves the final result from the URI of the original request
+	FinalStateViaOriginalUri FinalStateVia = "original-uri"
+)
+
+// finalResultLocator is implemented by Pollers which can determine where the final result of the operation is
+type finalResultLocator interface {
+	finalResultUri(finalStateVia FinalStateVia) (*string, error)
+}
+
+// resumableResultPoller is implemented by Pollers which can be resumed by ResumeResultPoller
+type resumableResultPoller interface {
+	resumeToken() resumeToken
+}
+
+// ResultPoller is a Poller which, once the Long Running Operation has completed, retrieves the final result
+// of the operation and unmarshals it into a `T`
+type ResultPoller[T any] struct {
+	baseClient    *BaseClient
+	finalResponse *http.Response
+	finalStateVia FinalStateVia
+	poller        Poller
+	result        *T
+}
+
+// NewResultPoller returns a ResultPoller which polls using the specified Poller, retrieving the final result
+// of the operation from the location specified by `finalStateVia`
+func NewResultPoller[T any](poller Poller, baseClient *BaseClient, finalStateVia FinalStateVia) *ResultPoller[T] {
+	return &ResultPoller[T]{
+		baseClient:    baseClient,
+		finalStateVia: finalStateVia,
+		poller:        poller,
+	}
+}
+
+func (p ResultPoller[T]) GetOriginalResponse() *http.Response {
+	return p.poller.GetOriginalResponse()
+}
+
+func (p ResultPoller[T]) GetLatestPollResponse() *http.Response {
+	return p.poller.GetLatestPollResponse()
+}
+
+// GetFinalResponse returns the HTTP Response containing the final result of the operation,
+// which is nil until the operation has completed
+func (p ResultPoller[T]) GetFinalResponse() *http.Response {
+	return p.finalResponse
+}
+
+// ResumeToken returns a token which can be used to resume polling later (e.g. in another process) using
+// ResumeResultPoller, which includes where the final result of the operation is retrieved from
+func (p ResultPoller[T]) ResumeToken() (string, error) {
+	resumable, ok := p.poller.(resumableResultPoller)
+	if !ok {
+		return "", fmt.Errorf("unable to build a resume token for a %T", p.poller)
+	}
+
+	token := resumable.resumeToken()
+	token.FinalStateVia = p.finalStateVia
+	return token.encode()
+}
+
+// Result returns the final result of the operation, which is nil until the operation has completed
+func (p ResultPoller[T]) Result() *T {
+	return p.result
+}
+
+// PollUntilDone polls until the operation has completed and then retrieves the final result, which
+// is subsequently available via `Result`
+func (p *ResultPoller[T]) PollUntilDone(ctx context.Context) error {
+	if err := p.poller.PollUntilDone(ctx); err != nil {
+		return err
+	}
+
+	locator, ok := p.poller.(finalResultLocator)
+	if !ok {
+		return fmt.Errorf("unable to determine the location of the final result for a %T", p.poller)
+	}
+	uri, err := locator.finalResultUri(p.finalStateVia)
+	if err != nil {
+		return fmt.Errorf("determining the location of the final result: %+v", err)
+	}
+
+	resp := p.poller.GetLatestPollResponse()
+	if uri != nil {
+		input := GetHttpRequestInput{
+			ExpectedStatusCodes: []int{
+				http.StatusOK,
+			},
+			Uri: *uri,
+		}
+		resp, err = p.baseClient.Get(ctx, input)
+		if err != nil {
+			return fmt.Errorf("retrieving the final result: %w", err)
+		}
+	}
+	if resp == nil {
+		return fmt.Errorf("retrieving the final result: no response was returned")
+	}
+
+	var out T
+	if err := decodePollResponse(resp, &out); err != nil {
+		return fmt.Errorf("decoding the final result: %+v", err)
+	}
+
+	p.finalResponse = resp
+	p.result = &out
+	return nil
+}
@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type resultPollerTestModel struct {
+	Name string `json:"name"`
+}
+
+func TestResultPollerRetrievesResultFromOriginalUri(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/operation":
+			fmt.Fprint(w, `{"status": "Succeeded"}`)
+		case "/resource":
+			fmt.Fprint(w, `{"name": "example"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	testData := []struct {
+		method        string
+		finalStateVia FinalStateVia
+	}{
+		{
+			method:        http.MethodPut,
+			finalStateVia: FinalStateViaDefault,
+		},
+		{
+			method:        http.MethodPatch,
+			finalStateVia: FinalStateViaDefault,
+		},
+		{
+			method:        http.MethodPost,
+			finalStateVia: FinalStateViaOriginalUri,
+		},
+	}
+	for _, v := range testData {
+		requests = make([]string, 0)
+		poller := NewResultPoller[resultPollerTestModel](&LongRunningOperationPoller{
+			asyncOperation: true,
+			baseClient:     &client,
+			method:         v.method,
+			originalUri:    server.URL + "/resource",
+			pollInterval:   time.Second,
+			pollLocation:   server.URL + "/operation",
+		}, &client, v.finalStateVia)
+		if poller.Result() != nil {
+			t.Fatalf("expected no result for %s before polling", v.method)
+		}
+
+		if err := poller.PollUntilDone(context.TODO()); err != nil {
+			t.Fatalf("polling for %s: %+v", v.method, err)
+		}
+		if len(requests) != 2 || requests[1] != "/resource" {
+			t.Fatalf("expected the final result to be retrieved from the original uri for %s but got %+v", v.method, requests)
+		}
+		if result := poller.Result(); result == nil || result.Name != "example" {
+			t.Fatalf("expected the result for %s to have the name %q but got %+v", v.method, "example", result)
+		}
+		if poller.GetFinalResponse() == nil {
+			t.Fatalf("expected a final response for %s", v.method)
+		}
+	}
+}
+
+func TestResultPollerRetrievesResultFromLocation(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/operation":
+			fmt.Fprint(w, `{"status": "Succeeded"}`)
+		case "/location":
+			fmt.Fprint(w, `{"name": "from-location"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+
+	// when the `Azure-AsyncOperation` header is polled the result is retrieved from the `Location` header
+	poller := NewResultPoller[resultPollerTestModel](&LongRunningOperationPoller{
+		asyncOperation: true,
+		baseClient:     &client,
+		location:       server.URL + "/location",
+		method:         http.MethodPost,
+		pollInterval:   time.Second,
+		pollLocation:   server.URL + "/operation",
+	}, &client, FinalStateViaLocation)
+	if err := poller.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[1] != "/location" {
+		t.Fatalf("expected the final result to be retrieved from the location but got %+v", requests)
+	}
+	if result := poller.Result(); result == nil || result.Name != "from-location" {
+		t.Fatalf("expected the result to have the name %q but got %+v", "from-location", result)
+	}
+
+	// whereas when the `Location` header is polled the final poll response contains the result
+	requests = make([]string, 0)
+	poller = NewResultPoller[resultPollerTestModel](&LongRunningOperationPoller{
+		baseClient:   &client,
+		location:     server.URL + "/location",
+		method:       http.MethodPost,
+		pollInterval: time.Second,
+		pollLocation: server.URL + "/location",
+	}, &client, FinalStateViaDefault)
+	if err := poller.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected a single request but got %+v", requests)
+	}
+	if result := poller.Result(); result == nil || result.Name != "from-location" {
+		t.Fatalf("expected the result to have the name %q but got %+v", "from-location", result)
+	}
+}
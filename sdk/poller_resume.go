@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	longRunningOperationPollerType = "LongRunningOperation"
+	provisioningStatePollerType    = "ProvisioningState"
+)
+
+// resumeToken contains everything needed to rebuild a Poller, which is serialised as base64-encoded JSON
+type resumeToken struct {
+	// Type is the type of Poller, either `LongRunningOperation` or `ProvisioningState`
+	Type string `json:"type"`
+
+	// AsyncOperation specifies whether the PollLocation is from the `Azure-AsyncOperation` header
+	AsyncOperation bool `json:"asyncOperation,omitempty"`
+
+	// FinalStateVia specifies where the final result of the operation is retrieved from, for a ResultPoller
+	FinalStateVia FinalStateVia `json:"finalStateVia,omitempty"`
+
+	// Location is the `Location` header returned in the original response, used to retrieve the final result
+	Location string `json:"location,omitempty"`
+
+	// Method is the HTTP Method of the request which started the operation
+	Method string `json:"method"`
+
+	// OriginalUri is the URI of the request which started the operation
+	OriginalUri string `json:"originalUri"`
+
+	// PollInterval is the interval between polls
+	PollInterval time.Duration `json:"pollInterval"`
+
+	// PollLocation is the URI which is polled to determine the status of the operation
+	PollLocation string `json:"pollLocation"`
+}
+
+func (t resumeToken) encode() (string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("marshalling resume token: %+v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ResumePoller rebuilds the Poller from a token returned from `ResumeToken` - which allows polling for
+// a Long Running Operation to continue in another process. Since the original response isn't available,
+// `GetOriginalResponse` returns nil for the resumed Poller.
+func ResumePoller(token string, baseClient *BaseClient) (Poller, error) {
+	out, err := decodeResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return pollerFromResumeToken(*out, baseClient)
+}
+
+// ResumeResultPoller rebuilds the ResultPoller from a token returned from `ResumeToken`, which retrieves the
+// final result from the same location as the original ResultPoller once the operation has completed
+func ResumeResultPoller[T any](token string, baseClient *BaseClient) (*ResultPoller[T], error) {
+	out, err := decodeResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := pollerFromResumeToken(*out, baseClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResultPoller[T](poller, baseClient, out.FinalStateVia), nil
+}
+
+func decodeResumeToken(token string) (*resumeToken, error) {
+	data, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decoding resume token: %+v", err)
+	}
+
+	var out resumeToken
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("unmarshalling resume token: %+v", err)
+	}
+
+	return &out, nil
+}
+
+func pollerFromResumeToken(out resumeToken, baseClient *BaseClient) (Poller, error) {
+	if out.PollLocation == "" {
+		return nil, fmt.Errorf("the resume token doesn't contain a poll location")
+	}
+
+	switch out.Type {
+	case longRunningOperationPollerType:
+		return &LongRunningOperationPoller{
+			asyncOperation: out.AsyncOperation,
+			baseClient:     baseClient,
+			location:       out.Location,
+			method:         out.Method,
+			originalUri:    out.OriginalUri,
+			pollInterval:   out.PollInterval,
+			pollLocation:   out.PollLocation,
+		}, nil
+
+	case provisioningStatePollerType:
+		return &ProvisioningStatePoller{
+			baseClient:   baseClient,
+			method:       out.Method,
+			pollInterval: out.PollInterval,
+			pollLocation: out.PollLocation,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported poller type %q", out.Type)
+}
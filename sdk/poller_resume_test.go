@@ -0,0 +1,147 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResumePollerLongRunningOperation(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 2 {
+			fmt.Fprint(w, `{"status": "InProgress"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": "Succeeded"}`)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	original := LongRunningOperationPoller{
+		asyncOperation: true,
+		baseClient:     &client,
+		location:       "https://management.azure.com/operationResults/1234",
+		method:         http.MethodPut,
+		originalUri:    "https://management.azure.com/subscriptions/1234/resourceGroups/group1",
+		pollInterval:   30 * time.Second,
+		pollLocation:   server.URL,
+	}
+	token, err := original.ResumeToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the token is used to rebuild the poller, such as in another process
+	resumedClient := newTestBaseClient(clock)
+	poller, err := ResumePoller(token, &resumedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, ok := poller.(*LongRunningOperationPoller)
+	if !ok {
+		t.Fatalf("expected a LongRunningOperationPoller but got %T", poller)
+	}
+	if !resumed.asyncOperation || resumed.method != original.method || resumed.originalUri != original.originalUri || resumed.location != original.location {
+		t.Fatalf("expected the resumed poller to match the original but got %+v", resumed)
+	}
+	if resumed.GetOriginalResponse() != nil {
+		t.Fatal("expected the original response to be nil for a resumed poller")
+	}
+
+	if err := poller.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 {
+		t.Fatalf("expected 2 polls but got %d", polls)
+	}
+	if len(clock.delays) != 2 || clock.delays[0] != 30*time.Second {
+		t.Fatalf("expected 2 waits of 30s but got %+v", clock.delays)
+	}
+}
+
+func TestResumePollerProvisioningState(t *testing.T) {
+	original := ProvisioningStatePoller{
+		method:       http.MethodPatch,
+		pollInterval: 15 * time.Second,
+		pollLocation: "https://management.azure.com/subscriptions/1234/resourceGroups/group1",
+	}
+	token, err := original.ResumeToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	poller, err := ResumePoller(token, &client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, ok := poller.(*ProvisioningStatePoller)
+	if !ok {
+		t.Fatalf("expected a ProvisioningStatePoller but got %T", poller)
+	}
+	if resumed.method != original.method || resumed.pollInterval != original.pollInterval || resumed.pollLocation != original.pollLocation {
+		t.Fatalf("expected the resumed poller to match the original but got %+v", resumed)
+	}
+}
+
+func TestResumePollerInvalidToken(t *testing.T) {
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	for _, token := range []string{"", "not-base64!", "e30="} {
+		if _, err := ResumePoller(token, &client); err == nil {
+			t.Fatalf("expected an error for the token %q", token)
+		}
+	}
+}
+
+func TestResumeResultPoller(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/operation":
+			fmt.Fprint(w, `{"status": "Succeeded"}`)
+		case "/location":
+			fmt.Fprint(w, `{"name": "from-location"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	original := NewResultPoller[resultPollerTestModel](&LongRunningOperationPoller{
+		asyncOperation: true,
+		baseClient:     &client,
+		location:       server.URL + "/location",
+		method:         http.MethodPut,
+		originalUri:    server.URL + "/resource",
+		pollInterval:   time.Second,
+		pollLocation:   server.URL + "/operation",
+	}, &client, FinalStateViaLocation)
+	token, err := original.ResumeToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the final result must be retrieved from the `Location` rather than the original uri (the default for a PUT)
+	resumedClient := newTestBaseClient(&fakeClock{now: time.Now()})
+	resumed, err := ResumeResultPoller[resultPollerTestModel](token, &resumedClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resumed.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[1] != "/location" {
+		t.Fatalf("expected the final result to be retrieved from the location but got %+v", requests)
+	}
+	if result := resumed.Result(); result == nil || result.Name != "from-location" {
+		t.Fatalf("expected the result to have the name %q but got %+v", "from-location", result)
+	}
+}
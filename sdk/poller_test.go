@@ -0,0 +1,229 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tombuildsstuff/pandora/sdk/environments"
+)
+
+func TestLongRunningOperationPollerUsesClock(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	poller := LongRunningOperationPoller{
+		baseClient:   &client,
+		pollInterval: 15 * time.Second,
+		pollLocation: server.URL,
+	}
+	if err := poller.PollUntilDone(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if polls != 3 {
+		t.Fatalf("expected 3 polls but got %d", polls)
+	}
+	if len(clock.delays) != 3 || clock.delays[0] != 15*time.Second {
+		t.Fatalf("expected 3 waits of 15s but got %+v", clock.delays)
+	}
+}
+
+func TestProvisioningStatePollerReturnsWhenContextIsCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no polls once the context is cancelled")
+	}))
+	defer server.Close()
+
+	// the system clock is used here to ensure that cancellation doesn't wait for the poll interval
+	client := NewBaseClient(environments.Public, &fakeAuthorizer{}, ClientOptions{})
+	poller := ProvisioningStatePoller{
+		baseClient:   &client,
+		pollInterval: time.Hour,
+		pollLocation: server.URL,
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	err := poller.PollUntilDone(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected `context.Canceled` but got %+v", err)
+	}
+}
+
+func TestPollerTimeout(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Retry-After", "15")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	client.pollingTimeout = time.Minute
+	poller := LongRunningOperationPoller{
+		baseClient:   &client,
+		pollInterval: 15 * time.Second,
+		pollLocation: server.URL,
+	}
+
+	err := poller.PollUntilDone(context.TODO())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected `context.DeadlineExceeded` but got %+v", err)
+	}
+	if polls != 4 {
+		t.Fatalf("expected 4 polls within the timeout but got %d", polls)
+	}
+}
+
+func TestPollerTimeoutShorterThanInterval(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Retry-After", "15")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client := newTestBaseClient(clock)
+	client.pollingTimeout = 10 * time.Second
+	poller := LongRunningOperationPoller{
+		baseClient:   &client,
+		pollInterval: 15 * time.Second,
+		pollLocation: server.URL,
+	}
+
+	err := poller.PollUntilDone(context.TODO())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected `context.DeadlineExceeded` but got %+v", err)
+	}
+
+	// the operation should be polled once at the deadline, rather than timing out without polling
+	if polls != 1 {
+		t.Fatalf("expected 1 poll at the deadline but got %d", polls)
+	}
+	if len(clock.delays) != 1 || clock.delays[0] != 10*time.Second {
+		t.Fatalf("expected a single wait of 10s but got %+v", clock.delays)
+	}
+}
+
+func TestLongRunningOperationPollerStatus(t *testing.T) {
+	testData := []struct {
+		responses []string
+		polls     int
+		status    string
+		errorCode string
+	}{
+		{
+			responses: []string{`{"status":"InProgress"}`, `{"status":"Succeeded"}`},
+			polls:     2,
+		},
+		{
+			responses: []string{`{"status":"InProgress"}`, `{"status":"Failed","error":{"code":"NamespaceQuotaExceeded","message":"Quota exceeded."}}`},
+			polls:     2,
+			status:    "Failed",
+			errorCode: "NamespaceQuotaExceeded",
+		},
+		{
+			responses: []string{`{"status":"Canceled"}`},
+			polls:     1,
+			status:    "Canceled",
+		},
+		{
+			// unknown statuses are terminal, rather than being polled forever
+			responses: []string{`{"status":"Accepted"}`, `{"status":"Updating"}`, `{"status":"Deleted"}`},
+			polls:     3,
+			status:    "Deleted",
+		},
+	}
+	for _, v := range testData {
+		polls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(v.responses[polls]))
+			polls++
+		}))
+
+		client := newTestBaseClient(&fakeClock{now: time.Now()})
+		poller := LongRunningOperationPoller{
+			asyncOperation: true,
+			baseClient:     &client,
+			pollInterval:   15 * time.Second,
+			pollLocation:   server.URL,
+		}
+		err := poller.PollUntilDone(context.TODO())
+		server.Close()
+
+		if polls != v.polls {
+			t.Fatalf("expected %d polls but got %d", v.polls, polls)
+		}
+		if v.status == "" {
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		var operationErr LongRunningOperationError
+		if !errors.As(err, &operationErr) {
+			t.Fatalf("expected a LongRunningOperationError but got %+v", err)
+		}
+		if operationErr.Status != v.status {
+			t.Fatalf("expected the status to be %q but got %q", v.status, operationErr.Status)
+		}
+		if v.errorCode != "" && (operationErr.Detail == nil || operationErr.Detail.Code != v.errorCode) {
+			t.Fatalf("expected the error code to be %q but got %+v", v.errorCode, operationErr.Detail)
+		}
+	}
+}
+
+func TestProvisioningStatePollerFailed(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		if polls == 1 {
+			w.Write([]byte(`{"properties":{"provisioningState":"Creating"}}`))
+			return
+		}
+		w.Write([]byte(`{"properties":{"provisioningState":"Failed"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestBaseClient(&fakeClock{now: time.Now()})
+	poller := ProvisioningStatePoller{
+		baseClient:   &client,
+		pollInterval: 15 * time.Second,
+		pollLocation: server.URL,
+	}
+
+	err := poller.PollUntilDone(context.TODO())
+	var operationErr LongRunningOperationError
+	if !errors.As(err, &operationErr) || operationErr.Status != "Failed" {
+		t.Fatalf("expected a LongRunningOperationError with the status `Failed` but got %+v", err)
+	}
+	if polls != 2 {
+		t.Fatalf("expected 2 polls but got %d", polls)
+	}
+}
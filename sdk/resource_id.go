@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SegmentType is the type of a Segment within a Resource ID
+type SegmentType string
+
+const (
+	// ResourceGroupSegmentType is the name of a Resource Group, e.g. `example-resources`
+	ResourceGroupSegmentType SegmentType = "ResourceGroup"
+
+	// ResourceProviderSegmentType is the (fixed) name of a Resource Provider, e.g. `Microsoft.EventHub`
+	ResourceProviderSegmentType SegmentType = "ResourceProvider"
+
+	// ScopeSegmentType is a parent Resource ID spanning multiple segments, e.g. `/subscriptions/1234/resourceGroups/example`,
+	// which is used by extension resources such as Role Assignments and Locks
+	ScopeSegmentType SegmentType = "Scope"
+
+	// StaticSegmentType is a fixed value, e.g. `resourceGroups` or `namespaces`
+	StaticSegmentType SegmentType = "Static"
+
+	// SubscriptionIdSegmentType is the ID of a Subscription, e.g. `12345678-1234-9876-4563-123456789012`
+	SubscriptionIdSegmentType SegmentType = "SubscriptionId"
+
+	// UserSpecifiedSegmentType is a user-specified value, such as the name of a resource, e.g. `example-namespace`
+	UserSpecifiedSegmentType SegmentType = "UserSpecified"
+)
+
+// Segment is a single component of a Resource ID, for example `resourceGroups` or the name of the Resource Group
+type Segment struct {
+	// Name is the name of this Segment, which the parsed value is keyed by for user-specified segments
+	Name string
+
+	// Type is the type of this Segment
+	Type SegmentType
+
+	// ExampleValue is an example value for this Segment, used to describe the expected format of the Resource ID
+	ExampleValue string
+
+	// FixedValue is the value for Resource Provider and Static segments, which must match exactly
+	FixedValue *string
+}
+
+// ResourceGroupSegment returns a Segment containing the name of a Resource Group
+func ResourceGroupSegment(name, exampleValue string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         ResourceGroupSegmentType,
+		ExampleValue: exampleValue,
+	}
+}
+
+// ResourceProviderSegment returns a Segment containing the (fixed) name of a Resource Provider
+func ResourceProviderSegment(name, resourceProvider string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         ResourceProviderSegmentType,
+		ExampleValue: resourceProvider,
+		FixedValue:   &resourceProvider,
+	}
+}
+
+// ScopeSegment returns a Segment containing a parent Resource ID, which can span multiple segments
+func ScopeSegment(name, exampleValue string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         ScopeSegmentType,
+		ExampleValue: exampleValue,
+	}
+}
+
+// StaticSegment returns a Segment containing a fixed value, such as `resourceGroups`
+func StaticSegment(name, value string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         StaticSegmentType,
+		ExampleValue: value,
+		FixedValue:   &value,
+	}
+}
+
+// SubscriptionIdSegment returns a Segment containing the ID of a Subscription
+func SubscriptionIdSegment(name, exampleValue string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         SubscriptionIdSegmentType,
+		ExampleValue: exampleValue,
+	}
+}
+
+// UserSpecifiedSegment returns a Segment containing a user-specified value, such as the name of a resource
+func UserSpecifiedSegment(name, exampleValue string) Segment {
+	return Segment{
+		Name:         name,
+		Type:         UserSpecifiedSegmentType,
+		ExampleValue: exampleValue,
+	}
+}
+
+// ExampleResourceID returns an example of a Resource ID comprised of the specified segments
+func ExampleResourceID(segments []Segment) string {
+	components := make([]string, 0)
+	for _, segment := range segments {
+		components = append(components, strings.TrimPrefix(segment.ExampleValue, "/"))
+	}
+
+	return fmt.Sprintf("/%s", strings.Join(components, "/"))
+}
+
+// ResourceIDParser parses Resource IDs into their component parts, validating these against the expected Segments
+type ResourceIDParser struct {
+	segments []Segment
+}
+
+func NewResourceIDParser(segments []Segment) ResourceIDParser {
+	return ResourceIDParser{
+		segments: segments,
+	}
+}
+
+// ParseResult contains the values for each of the user-specified segments within the Resource ID, keyed by name
+type ParseResult struct {
+	Parsed map[string]string
+}
+
+// Parse parses the Resource ID `input`, returning the values for each of the segments (other than the
+// Resource Provider and Static segments, which are validated).
+//
+// The Resource Provider and Static segments must match exactly, unless `insensitively` is set - which should only
+// be used for Resource IDs returned by the API, rather than user input, since some Resource Providers return these
+// with different casing.
+func (p ResourceIDParser) Parse(input string, insensitively bool) (*ParseResult, error) {
+	if input == "" {
+		return nil, fmt.Errorf("the Resource ID was empty")
+	}
+
+	actual := make([]string, 0)
+	if trimmed := strings.Trim(input, "/"); trimmed != "" {
+		actual = strings.Split(trimmed, "/")
+	}
+	parsed := make(map[string]string)
+	position := 0
+
+	for i, segment := range p.segments {
+		// the scope can be empty, since the tenant-root scope is `/`
+		if position >= len(actual) && segment.Type != ScopeSegmentType {
+			return nil, fmt.Errorf("the segment %q was missing, expected a Resource ID in the format %q", segment.Name, ExampleResourceID(p.segments))
+		}
+
+		switch segment.Type {
+		case ResourceProviderSegmentType, StaticSegmentType:
+			{
+				value := actual[position]
+				matches := value == *segment.FixedValue
+				if insensitively {
+					matches = strings.EqualFold(value, *segment.FixedValue)
+				}
+				if !matches {
+					return nil, fmt.Errorf("expected the segment %q to be %q but got %q, expected a Resource ID in the format %q", segment.Name, *segment.FixedValue, value, ExampleResourceID(p.segments))
+				}
+				position++
+			}
+
+		case ScopeSegmentType:
+			{
+				// the scope consumes everything up until the segments which follow it
+				remainingSegments := len(p.segments) - i - 1
+				end := len(actual) - remainingSegments
+				if end < position {
+					return nil, fmt.Errorf("the segment %q was missing, expected a Resource ID in the format %q", segment.Name, ExampleResourceID(p.segments))
+				}
+
+				parsed[segment.Name] = fmt.Sprintf("/%s", strings.Join(actual[position:end], "/"))
+				position = end
+			}
+
+		default:
+			{
+				value := actual[position]
+				if value == "" {
+					return nil, fmt.Errorf("the segment %q was empty", segment.Name)
+				}
+
+				parsed[segment.Name] = value
+				position++
+			}
+		}
+	}
+
+	if position < len(actual) {
+		unexpected := strings.Join(actual[position:], "/")
+		return nil, fmt.Errorf("unexpected segments %q at the end of the Resource ID, expected a Resource ID in the format %q", unexpected, ExampleResourceID(p.segments))
+	}
+
+	return &ParseResult{
+		Parsed: parsed,
+	}, nil
+}
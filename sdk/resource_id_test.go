@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceIDParser(t *testing.T) {
+	parser := NewResourceIDParser([]Segment{
+		StaticSegment("staticSubscriptions", "subscriptions"),
+		SubscriptionIdSegment("subscriptionId", "12345678-1234-9876-4563-123456789012"),
+		StaticSegment("staticResourceGroups", "resourceGroups"),
+		ResourceGroupSegment("resourceGroup", "example-resources"),
+		StaticSegment("staticProviders", "providers"),
+		ResourceProviderSegment("staticMicrosoftEventHub", "Microsoft.EventHub"),
+		StaticSegment("staticNamespaces", "namespaces"),
+		UserSpecifiedSegment("name", "example-namespace"),
+	})
+	testData := []struct {
+		input         string
+		insensitively bool
+		expected      map[string]string
+		error         string
+	}{
+		{
+			input: "/subscriptions/1234/resourceGroups/group1/providers/Microsoft.EventHub/namespaces/namespace1",
+			expected: map[string]string{
+				"subscriptionId": "1234",
+				"resourceGroup":  "group1",
+				"name":           "namespace1",
+			},
+		},
+		{
+			// some Resource Providers return IDs using different casing
+			input:         "/subscriptions/1234/resourcegroups/group1/providers/Microsoft.Eventhub/Namespaces/namespace1",
+			insensitively: true,
+			expected: map[string]string{
+				"subscriptionId": "1234",
+				"resourceGroup":  "group1",
+				"name":           "namespace1",
+			},
+		},
+		{
+			input: "/subscriptions/1234/resourcegroups/group1/providers/Microsoft.EventHub/namespaces/namespace1",
+			error: `expected the segment "staticResourceGroups" to be "resourceGroups" but got "resourcegroups"`,
+		},
+		{
+			input: "/subscriptions/1234/resourceGroups/group1/providers/Microsoft.ServiceBus/namespaces/namespace1",
+			error: `expected the segment "staticMicrosoftEventHub" to be "Microsoft.EventHub" but got "Microsoft.ServiceBus"`,
+		},
+		{
+			input: "/subscriptions/1234/resourceGroups/group1/providers/Microsoft.EventHub/namespaces",
+			error: `the segment "name" was missing`,
+		},
+		{
+			input: "/subscriptions/1234/resourceGroups/group1",
+			error: `the segment "staticProviders" was missing, expected a Resource ID in the format "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/example-resources/providers/Microsoft.EventHub/namespaces/example-namespace"`,
+		},
+		{
+			input: "/subscriptions/1234/resourceGroups/group1/providers/Microsoft.EventHub/namespaces/namespace1/eventhubs/hub1",
+			error: `unexpected segments "eventhubs/hub1"`,
+		},
+		{
+			input: "",
+			error: "the Resource ID was empty",
+		},
+	}
+	for _, v := range testData {
+		actual, err := parser.Parse(v.input, v.insensitively)
+		if v.error != "" {
+			if err == nil || !strings.Contains(err.Error(), v.error) {
+				t.Fatalf("expected an error containing %q for %q but got %+v", v.error, v.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parsing %q: %+v", v.input, err)
+		}
+
+		for key, expected := range v.expected {
+			if actual.Parsed[key] != expected {
+				t.Fatalf("expected the segment %q to be %q for %q but got %q", key, expected, v.input, actual.Parsed[key])
+			}
+		}
+	}
+}
+
+func TestResourceIDParserScope(t *testing.T) {
+	parser := NewResourceIDParser([]Segment{
+		ScopeSegment("scope", "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/example-resources"),
+		StaticSegment("staticProviders", "providers"),
+		ResourceProviderSegment("staticMicrosoftAuthorization", "Microsoft.Authorization"),
+		StaticSegment("staticLocks", "locks"),
+		UserSpecifiedSegment("name", "example-lock"),
+	})
+
+	actual, err := parser.Parse("/subscriptions/1234/resourceGroups/group1/providers/Microsoft.Authorization/locks/lock1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Parsed["scope"] != "/subscriptions/1234/resourceGroups/group1" {
+		t.Fatalf("expected the scope to be %q but got %q", "/subscriptions/1234/resourceGroups/group1", actual.Parsed["scope"])
+	}
+	if actual.Parsed["name"] != "lock1" {
+		t.Fatalf("expected the name to be %q but got %q", "lock1", actual.Parsed["name"])
+	}
+
+	testData := map[string]string{
+		// the tenant-root scope
+		"/providers/Microsoft.Authorization/locks/lock1":                                                        "/",
+		"/providers/Microsoft.Management/managementGroups/group1/providers/Microsoft.Authorization/locks/lock1": "/providers/Microsoft.Management/managementGroups/group1",
+	}
+	for input, expected := range testData {
+		actual, err := parser.Parse(input, false)
+		if err != nil {
+			t.Fatalf("parsing %q: %+v", input, err)
+		}
+		if actual.Parsed["scope"] != expected {
+			t.Fatalf("expected the scope for %q to be %q but got %q", input, expected, actual.Parsed["scope"])
+		}
+	}
+
+	if _, err := parser.Parse("/locks/lock1", false); err == nil {
+		t.Fatal("expected an error when the segments following the scope are missing")
+	}
+}